@@ -0,0 +1,156 @@
+package serrors
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+type attrError struct {
+	err   error
+	attrs []slog.Attr
+}
+
+func (e *attrError) Error() string {
+	return e.err.Error()
+}
+
+func (e *attrError) Unwrap() error {
+	return e.err
+}
+
+// Format implements fmt.Formatter in the same way as the errors that have a StackTrace.
+func (e *attrError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('#') {
+		_, _ = fmt.Fprintf(s, "&serrors.attrError{err:%#v, attrs:%#v}", e.err, e.attrs)
+		return
+	}
+	formatError(s, verb, e)
+}
+
+// With attaches attributes to err.
+//
+// The args are converted to slog.Attr in the same way as slog.Logger.Log:
+// a string followed by a value is treated as a key-value pair, and a slog.Attr is used as-is.
+//
+// If err does not have a StackTrace, this function also attaches the current StackTrace.
+//
+// Also, if err is nil, this function returns nil.
+func With(err error, args ...any) error {
+	return withAttrs(err, argsToAttrs(args))
+}
+
+// WithAttrs attaches slog.Attr to err.
+//
+// If err does not have a StackTrace, this function also attaches the current StackTrace.
+//
+// Also, if err is nil, this function returns nil.
+func WithAttrs(err error, attrs ...slog.Attr) error {
+	return withAttrs(err, attrs)
+}
+
+// NewWith creates an error with a StackTrace and attributes.
+//
+// The args are handled in the same way as With.
+func NewWith(msg string, args ...any) error {
+	return withAttrs(errors.New(msg), argsToAttrs(args))
+}
+
+// ErrorfWith creates an error with a StackTrace and attributes in the same way as Errorf.
+//
+// Because the format arguments are variadic, the attributes are given as a slice of slog.Attr.
+func ErrorfWith(attrs []slog.Attr, format string, args ...any) error {
+	return withAttrs(fmt.Errorf(format, args...), attrs)
+}
+
+func withAttrs(err error, attrs []slog.Attr) error {
+	if err == nil {
+		return nil
+	}
+
+	if getStackTraceError(err) == nil {
+		pcs, omitted := callers(2, MaxStackDepth()) // withAttrs -> caller (With/WithAttrs/NewWith/ErrorfWith)
+		err = &stackTraceError{
+			err:     err,
+			pcs:     pcs,
+			omitted: omitted,
+		}
+	}
+
+	return &attrError{
+		err:   err,
+		attrs: attrs,
+	}
+}
+
+// Attributes returns the attributes attached to err.
+//
+// This function walks the whole error chain, including errors that have an Unwrap() []error function.
+// The attributes are returned in depth-first order, so attributes attached by outer errors come first.
+//
+// If err does not have any attributes, this function returns nil.
+func Attributes(err error) []slog.Attr {
+	var attrs []slog.Attr
+	collectAttributes(err, &attrs)
+	return attrs
+}
+
+func collectAttributes(err error, attrs *[]slog.Attr) {
+	switch x := err.(type) {
+	case *attrError:
+		*attrs = append(*attrs, x.attrs...)
+		collectAttributes(x.err, attrs)
+	case interface{ Unwrap() error }:
+		collectAttributes(x.Unwrap(), attrs)
+	case interface{ Unwrap() []error }:
+		for _, err := range x.Unwrap() {
+			collectAttributes(err, attrs)
+		}
+	}
+}
+
+const badKey = "!BADKEY"
+
+func argsToAttrs(args []any) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(args)/2)
+	for len(args) > 0 {
+		switch x := args[0].(type) {
+		case string:
+			if len(args) == 1 {
+				attrs = append(attrs, slog.String(badKey, x))
+				args = nil
+			} else {
+				attrs = append(attrs, slog.Any(x, args[1]))
+				args = args[2:]
+			}
+		case slog.Attr:
+			attrs = append(attrs, x)
+			args = args[1:]
+		default:
+			attrs = append(attrs, slog.Any(badKey, x))
+			args = args[1:]
+		}
+	}
+	return attrs
+}
+
+// AttrValue converts the value of an attribute into a value that can be encoded by encoding/json.
+//
+// Groups are converted into map[string]any, and errors are converted into their messages
+// because encoding/json usually encodes them as empty objects. Other values are returned as slog.Value.Any returns.
+func AttrValue(v slog.Value) any {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := make(map[string]any, len(v.Group()))
+		for _, attr := range v.Group() {
+			group[attr.Key] = AttrValue(attr.Value)
+		}
+		return group
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+	}
+	return v.Any()
+}
@@ -0,0 +1,290 @@
+package serrors
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestWith(t *testing.T) {
+	serr := New("test")
+	tests := []struct {
+		name                string
+		err                 error
+		args                []any
+		wantNil             bool
+		wantAttrs           []slog.Attr
+		wantSameStackTrace  bool
+		wantStackTraceOwner string
+	}{
+		{
+			name:    "nil",
+			err:     nil,
+			args:    []any{"key", "value"},
+			wantNil: true,
+		},
+		{
+			name:                "attach stack trace",
+			err:                 errors.New("test"),
+			args:                []any{"key", "value"},
+			wantAttrs:           []slog.Attr{slog.String("key", "value")},
+			wantStackTraceOwner: "github.com/Siroshun09/serrors.TestWith.func1",
+		},
+		{
+			name:               "keep stack trace",
+			err:                serr,
+			args:               []any{"key", 1},
+			wantAttrs:          []slog.Attr{slog.Int("key", 1)},
+			wantSameStackTrace: true,
+		},
+		{
+			name:               "keep stack trace (wrapped)",
+			err:                fmt.Errorf("wrap: %w", serr),
+			args:               []any{slog.Bool("key", true)},
+			wantAttrs:          []slog.Attr{slog.Bool("key", true)},
+			wantSameStackTrace: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := With(tt.err, tt.args...)
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("With() = %v, want nil", got)
+				}
+				return
+			}
+
+			if got.Error() != tt.err.Error() {
+				t.Errorf("With().Error() = %v, want %v", got.Error(), tt.err.Error())
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("With() = %v, want to wrap %v", got, tt.err)
+			}
+			if attrs := Attributes(got); !attrsEqual(attrs, tt.wantAttrs) {
+				t.Errorf("Attributes() = %v, want %v", attrs, tt.wantAttrs)
+			}
+
+			stackTrace, ok := GetAttachedStackTrace(got)
+			switch {
+			case !ok:
+				t.Errorf("With() = %v, want to have a stack trace", got)
+			case tt.wantSameStackTrace:
+				if !reflect.DeepEqual(stackTrace, GetStackTrace(serr)) {
+					t.Errorf("stack trace = %v, want %v", stackTrace, GetStackTrace(serr))
+				}
+			case stackTrace[0].Name != tt.wantStackTraceOwner:
+				t.Errorf("stack trace = %v, want to start with %s", stackTrace, tt.wantStackTraceOwner)
+			}
+		})
+	}
+}
+
+func TestWithAttrs(t *testing.T) {
+	if got := WithAttrs(nil, slog.String("key", "value")); got != nil {
+		t.Errorf("WithAttrs() = %v, want nil", got)
+	}
+
+	attrs := []slog.Attr{slog.String("key1", "value"), slog.Int("key2", 2)}
+	err := WithAttrs(errors.New("test"), attrs...)
+	if got := Attributes(err); !attrsEqual(got, attrs) {
+		t.Errorf("Attributes() = %v, want %v", got, attrs)
+	}
+}
+
+func TestNewWith(t *testing.T) {
+	err := NewWith("test", "key", "value")
+	if err.Error() != "test" {
+		t.Errorf("NewWith().Error() = %v, want test", err.Error())
+	}
+
+	wantAttrs := []slog.Attr{slog.String("key", "value")}
+	if got := Attributes(err); !attrsEqual(got, wantAttrs) {
+		t.Errorf("Attributes() = %v, want %v", got, wantAttrs)
+	}
+
+	stackTrace, ok := GetAttachedStackTrace(err)
+	if !ok || stackTrace[0].Name != "github.com/Siroshun09/serrors.TestNewWith" {
+		t.Errorf("GetAttachedStackTrace() = (%v, %v), want to start with TestNewWith", stackTrace, ok)
+	}
+}
+
+func TestErrorfWith(t *testing.T) {
+	base := errors.New("not found")
+	err := ErrorfWith([]slog.Attr{slog.Int("user_id", 1)}, "failed to find user: %w", base)
+	if err.Error() != "failed to find user: not found" {
+		t.Errorf("ErrorfWith().Error() = %v, want failed to find user: not found", err.Error())
+	}
+	if !errors.Is(err, base) {
+		t.Errorf("errors.Is(err, base) = false, want true")
+	}
+
+	wantAttrs := []slog.Attr{slog.Int("user_id", 1)}
+	if got := Attributes(err); !attrsEqual(got, wantAttrs) {
+		t.Errorf("Attributes() = %v, want %v", got, wantAttrs)
+	}
+
+	stackTrace, ok := GetAttachedStackTrace(err)
+	if !ok || stackTrace[0].Name != "github.com/Siroshun09/serrors.TestErrorfWith" {
+		t.Errorf("GetAttachedStackTrace() = (%v, %v), want to start with TestErrorfWith", stackTrace, ok)
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	err1 := With(errors.New("test1"), "key1", 1)
+	err2 := With(errors.New("test2"), "key2", 2)
+
+	tests := []struct {
+		name string
+		err  error
+		want []slog.Attr
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "no attributes",
+			err:  New("test"),
+			want: nil,
+		},
+		{
+			name: "wrapped by fmt.Errorf",
+			err:  fmt.Errorf("wrap: %w", err1),
+			want: []slog.Attr{slog.Int("key1", 1)},
+		},
+		{
+			name: "outer attributes first",
+			err:  With(fmt.Errorf("wrap: %w", err1), "outer", true),
+			want: []slog.Attr{slog.Bool("outer", true), slog.Int("key1", 1)},
+		},
+		{
+			name: "joined errors",
+			err:  errors.Join(err1, errors.New("plain"), err2),
+			want: []slog.Attr{slog.Int("key1", 1), slog.Int("key2", 2)},
+		},
+		{
+			name: "contains nil error in multiple errors",
+			err:  &multipleErrorsWrapper{errs: []error{err2, nil, err1}},
+			want: []slog.Attr{slog.Int("key2", 2), slog.Int("key1", 1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Attributes(tt.err); !attrsEqual(got, tt.want) {
+				t.Errorf("Attributes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_argsToAttrs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []any
+		want []slog.Attr
+	}{
+		{
+			name: "empty",
+			args: nil,
+			want: []slog.Attr{},
+		},
+		{
+			name: "key-value pairs",
+			args: []any{"key1", "value", "key2", 2},
+			want: []slog.Attr{slog.String("key1", "value"), slog.Int("key2", 2)},
+		},
+		{
+			name: "slog.Attr",
+			args: []any{slog.Bool("key1", true), "key2", 2},
+			want: []slog.Attr{slog.Bool("key1", true), slog.Int("key2", 2)},
+		},
+		{
+			name: "missing value",
+			args: []any{"key1", "value", "key2"},
+			want: []slog.Attr{slog.String("key1", "value"), slog.String(badKey, "key2")},
+		},
+		{
+			name: "non-string key",
+			args: []any{1, "key", "value"},
+			want: []slog.Attr{slog.Int(badKey, 1), slog.String("key", "value")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := argsToAttrs(tt.args); !attrsEqual(got, tt.want) {
+				t.Errorf("argsToAttrs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttrError_Format(t *testing.T) {
+	err := &attrError{
+		err:   &stackTraceError{err: errors.New("test"), stackTrace: StackTrace{{Name: "Test", File: "test.go", Line: 1}}},
+		attrs: []slog.Attr{slog.String("key1", "value"), slog.Int("key2", 2)},
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "%v",
+			format: "%v",
+			want:   "test",
+		},
+		{
+			name:   "%+v",
+			format: "%+v",
+			want:   "test\nattributes: key1=value key2=2\nstacktrace: test\n\tTest (test.go:1)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, err); got != tt.want {
+				t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func attrsEqual(a, b []slog.Attr) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAttrValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value slog.Value
+		want  any
+	}{
+		{name: "int", value: slog.IntValue(1), want: int64(1)},
+		{name: "string", value: slog.StringValue("test"), want: "test"},
+		{name: "error", value: slog.AnyValue(errors.New("test")), want: "test"},
+		{
+			name:  "group",
+			value: slog.GroupValue(slog.String("key", "value"), slog.Group("nested", slog.Any("err", errors.New("test")))),
+			want:  map[string]any{"key": "value", "nested": map[string]any{"err": "test"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AttrValue(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AttrValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Siroshun09/serrors"
+)
+
+// trace is a stack trace found in the input.
+type trace struct {
+	// title is the error message or the goroutine that the stack trace belongs to.
+	title string
+	// stackTrace is the stack trace, the innermost call first.
+	stackTrace serrors.StackTrace
+	// omitted is the number of frames cut off from the end of stackTrace, as reported by serrors.StackTraceEntry.Omitted.
+	omitted int
+	// createdBy is the frame of the go statement that created the goroutine, if known.
+	createdBy serrors.FuncInfo
+}
+
+// parseInput finds the stack traces in data in the order they appear.
+//
+// The following formats are supported:
+//
+//   - the "stacktrace" blocks printed by errorlogs (one "name (file:line)" per line, optionally followed by "... N more frames")
+//   - JSON lines that have "stacktrace" values (and "omitted" next to them), encoded by json.Marshal or slog.JSONHandler
+//   - the output of unrecovered panics and goroutine dumps
+func parseInput(data []byte) []trace {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	var traces []trace
+	title := ""
+	inAttributes := false
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if isDumpStart(line) {
+			end := dumpEnd(lines, i)
+			if dumpTraces, ok := parseDump(lines[i:end]); ok {
+				traces = append(traces, dumpTraces...)
+				i = end - 1
+				continue
+			}
+		}
+
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "{") {
+			traces = append(traces, parseJSONLine(trimmed)...)
+			continue
+		}
+
+		if hasLogSuffix(line, "stacktrace") {
+			var stackTrace serrors.StackTrace
+			for i+1 < len(lines) {
+				var funcInfo serrors.FuncInfo
+				if funcInfo.UnmarshalText([]byte(lines[i+1])) != nil {
+					break
+				}
+				stackTrace = append(stackTrace, funcInfo)
+				i++
+			}
+			omitted := 0
+			if i+1 < len(lines) {
+				if n, ok := parseOmittedFrames(lines[i+1]); ok {
+					omitted = n
+					i++
+				}
+			}
+			traces = append(traces, trace{title: title, stackTrace: stackTrace, omitted: omitted})
+			inAttributes = false
+			continue
+		}
+
+		switch {
+		case hasLogSuffix(line, "attributes"):
+			inAttributes = true
+		case inAttributes && isAttribute(line):
+		case strings.Contains(line, "fingerprint: "), strings.TrimSpace(line) == "":
+		default:
+			// the log record that the following details belong to
+			title = line
+			inAttributes = false
+		}
+	}
+	return traces
+}
+
+// parseOmittedFrames parses the line formatted by serrors.FormatOmittedFrames, such as "... 3 more frames".
+func parseOmittedFrames(line string) (int, bool) {
+	switch line {
+	case serrors.FormatOmittedFrames(-1):
+		return -1, true
+	case serrors.FormatOmittedFrames(1):
+		return 1, true
+	}
+
+	s, found := strings.CutPrefix(line, "... ")
+	if !found {
+		return 0, false
+	}
+	if s, found = strings.CutSuffix(s, " more frames"); !found {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 1 {
+		return 0, false
+	}
+	return n, true
+}
+
+// hasLogSuffix reports whether line is word, optionally after the prefix of a log record such as "2006/01/02 15:04:05 ERROR ".
+func hasLogSuffix(line string, word string) bool {
+	return line == word || strings.HasSuffix(line, " "+word)
+}
+
+// isAttribute reports whether line is an attribute printed by errorlogs, such as "user_id=1".
+func isAttribute(line string) bool {
+	key, _, found := strings.Cut(line, "=")
+	return found && key != "" && !strings.ContainsAny(key, " \t")
+}
+
+func isDumpStart(line string) bool {
+	return strings.HasPrefix(line, "panic: ") || strings.HasPrefix(line, "fatal error: ") || isGoroutineHeader(line)
+}
+
+func isGoroutineHeader(line string) bool {
+	return strings.HasPrefix(line, "goroutine ") && strings.HasSuffix(line, "]:")
+}
+
+// dumpEnd returns the index of the first line after the panic or goroutine dump that starts at lines[start].
+func dumpEnd(lines []string, start int) int {
+	i := start + 1
+	// the rest of the panic message, such as "\tpanic: second" or "[signal SIGSEGV: ...]"
+	for i < len(lines) && lines[i] != "" && !isGoroutineHeader(lines[i]) {
+		if !strings.HasPrefix(lines[i], "\t") && !strings.HasPrefix(lines[i], "[") {
+			break
+		}
+		i++
+	}
+
+	for i < len(lines) {
+		line := lines[i]
+		switch {
+		case line == "", isGoroutineHeader(line), strings.HasPrefix(line, "\t"), strings.HasPrefix(line, "..."):
+			i++
+		case i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t"):
+			// a function call or "created by" followed by its location
+			i += 2
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+func parseDump(lines []string) ([]trace, bool) {
+	dump := []byte(strings.Join(lines, "\n"))
+
+	var goroutines []serrors.Goroutine
+	message := ""
+	if isGoroutineHeader(lines[0]) {
+		var err error
+		if goroutines, err = serrors.ParseGoroutineDump(dump); err != nil {
+			return nil, false
+		}
+	} else {
+		p, err := serrors.ParsePanic(dump)
+		if err != nil {
+			return nil, false
+		}
+		goroutines, message = p.Goroutines, p.Message
+	}
+
+	traces := make([]trace, 0, len(goroutines))
+	for i, g := range goroutines {
+		title := "goroutine " + strconv.FormatInt(g.ID, 10) + " [" + g.State + "]"
+		if i == 0 && message != "" {
+			title = message + " (" + title + ")"
+		}
+		traces = append(traces, trace{title: title, stackTrace: g.StackTrace, createdBy: g.CreatedBy})
+	}
+	return traces, true
+}
+
+// parseJSONLine finds the "stacktrace" values in the JSON object in line.
+//
+// The title of each trace is the nearest "error" or "msg" string in the enclosing objects.
+func parseJSONLine(line string) []trace {
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+
+	var v any
+	if decoder.Decode(&v) != nil {
+		return nil
+	}
+
+	var traces []trace
+	walkJSON(v, "", &traces)
+	return traces
+}
+
+func walkJSON(v any, title string, traces *[]trace) {
+	switch v := v.(type) {
+	case map[string]any:
+		for _, key := range []string{"msg", "error"} {
+			if s, ok := v[key].(string); ok {
+				title = s
+			}
+		}
+		for _, key := range sortedKeys(v) {
+			if key == "stacktrace" {
+				if stackTrace, ok := jsonStackTrace(v[key]); ok {
+					omitted, _ := v["omitted"].(json.Number)
+					n, _ := strconv.Atoi(omitted.String())
+					*traces = append(*traces, trace{title: title, stackTrace: stackTrace, omitted: n})
+					continue
+				}
+			}
+			walkJSON(v[key], title, traces)
+		}
+	case []any:
+		for _, elem := range v {
+			walkJSON(elem, title, traces)
+		}
+	}
+}
+
+// sortedKeys returns the keys of m in order, comparing numeric keys (such as the indices in "stacktraces") as numbers.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		x, errX := strconv.Atoi(a)
+		y, errY := strconv.Atoi(b)
+		if errX == nil && errY == nil {
+			return x - y
+		}
+		return strings.Compare(a, b)
+	})
+	return keys
+}
+
+// jsonStackTrace converts a StackTrace encoded by StackTrace.MarshalJSON ([{"name", "file", "line"}])
+// or StackTrace.LogValue ({"functions", "files", "lines"}).
+func jsonStackTrace(v any) (serrors.StackTrace, bool) {
+	switch v := v.(type) {
+	case []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, false
+		}
+		var stackTrace serrors.StackTrace
+		if err := json.Unmarshal(data, &stackTrace); err != nil {
+			return nil, false
+		}
+		return stackTrace, true
+	case map[string]any:
+		functions, ok1 := v["functions"].([]any)
+		files, ok2 := v["files"].([]any)
+		lines, ok3 := v["lines"].([]any)
+		if !ok1 || !ok2 || !ok3 || len(functions) != len(files) || len(functions) != len(lines) {
+			return nil, false
+		}
+
+		stackTrace := make(serrors.StackTrace, len(functions))
+		for i := range functions {
+			name, _ := functions[i].(string)
+			file, _ := files[i].(string)
+			line, _ := lines[i].(json.Number)
+			n, _ := strconv.Atoi(line.String())
+			stackTrace[i] = serrors.FuncInfo{Name: name, File: file, Line: n}
+		}
+		return stackTrace, true
+	}
+	return nil, false
+}
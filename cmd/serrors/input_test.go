@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+)
+
+func TestParseInput(t *testing.T) {
+	panicDump, err := os.ReadFile(filepath.Join("..", "..", "testdata", "dump", "panic.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []trace
+	}{
+		{
+			name: "errorlogs",
+			input: "2025/01/02 03:04:05 ERROR request failed: not found\n" +
+				"2025/01/02 03:04:05 DEBUG fingerprint: 0123456789abcdef\n" +
+				"2025/01/02 03:04:05 DEBUG attributes\n" +
+				"user_id=1\n" +
+				"2025/01/02 03:04:05 DEBUG stacktrace\n" +
+				"example.com/app.find (/src/app/find.go:10)\n" +
+				"example.com/app.main (/src/app/main.go:5)\n" +
+				"2025/01/02 03:04:06 INFO next request\n",
+			want: []trace{{
+				title: "2025/01/02 03:04:05 ERROR request failed: not found",
+				stackTrace: serrors.StackTrace{
+					{Name: "example.com/app.find", File: "/src/app/find.go", Line: 10},
+					{Name: "example.com/app.main", File: "/src/app/main.go", Line: 5},
+				},
+			}},
+		},
+		{
+			name: "errorlogs truncated",
+			input: "ERROR failed\n" +
+				"stacktrace\n" +
+				"example.com/app.find (/src/app/find.go:10)\n" +
+				"... more frames\n" +
+				"stacktrace\n" +
+				"example.com/app.load (/src/app/load.go:20)\n" +
+				"... 3 more frames\n",
+			want: []trace{
+				{title: "ERROR failed", stackTrace: serrors.StackTrace{{Name: "example.com/app.find", File: "/src/app/find.go", Line: 10}}, omitted: -1},
+				{title: "ERROR failed", stackTrace: serrors.StackTrace{{Name: "example.com/app.load", File: "/src/app/load.go", Line: 20}}, omitted: 3},
+			},
+		},
+		{
+			name:  "JSON array",
+			input: `{"level":"ERROR","msg":"failed","stacktrace":[{"name":"example.com/app.find","file":"/src/app/find.go","line":10}]}` + "\n",
+			want: []trace{{
+				title:      "failed",
+				stackTrace: serrors.StackTrace{{Name: "example.com/app.find", File: "/src/app/find.go", Line: 10}},
+			}},
+		},
+		{
+			name: "slog JSON",
+			input: `{"msg":"request failed","err":{"msg":"wrap: not found","stacktraces":{` +
+				`"0":{"error":"wrap: not found","stacktrace":{"functions":["example.com/app.find"],"files":["/src/app/find.go"],"lines":[10]}},` +
+				`"1":{"error":"not found","stacktrace":{"functions":["example.com/app.load"],"files":["/src/app/load.go"],"lines":[20]}}}}}`,
+			want: []trace{
+				{title: "wrap: not found", stackTrace: serrors.StackTrace{{Name: "example.com/app.find", File: "/src/app/find.go", Line: 10}}},
+				{title: "not found", stackTrace: serrors.StackTrace{{Name: "example.com/app.load", File: "/src/app/load.go", Line: 20}}},
+			},
+		},
+		{
+			name:  "slog JSON truncated",
+			input: `{"msg":"failed","err":{"stacktraces":{"0":{"error":"failed","stacktrace":{"functions":["example.com/app.find"],"files":["/src/app/find.go"],"lines":[10]},"omitted":2}}}}`,
+			want: []trace{
+				{title: "failed", stackTrace: serrors.StackTrace{{Name: "example.com/app.find", File: "/src/app/find.go", Line: 10}}, omitted: 2},
+			},
+		},
+		{
+			name:  "invalid JSON",
+			input: "{not json\n",
+		},
+		{
+			name:  "goroutine dump",
+			input: "log line\ngoroutine 1 [running]:\nmain.main()\n\t/src/app/main.go:5 +0x18\n\ngoroutine 2 [select]:\nmain.run(...)\n\t/src/app/run.go:8\ncreated by main.main in goroutine 1\n\t/src/app/main.go:4 +0x20\nafter dump\n",
+			want: []trace{
+				{title: "goroutine 1 [running]", stackTrace: serrors.StackTrace{{Name: "main.main", File: "/src/app/main.go", Line: 5}}},
+				{
+					title:      "goroutine 2 [select]",
+					stackTrace: serrors.StackTrace{{Name: "main.run", File: "/src/app/run.go", Line: 8}},
+					createdBy:  serrors.FuncInfo{Name: "main.main", File: "/src/app/main.go", Line: 4},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseInput([]byte(tt.input)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseInput() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("panic", func(t *testing.T) {
+		got := parseInput(append([]byte("starting server\n"), panicDump...))
+		if len(got) != 5 {
+			t.Fatalf("len(parseInput()) = %d, want 5", len(got))
+		}
+		want := "panic: runtime error: index out of range [3] with length 2 (goroutine 9 [running])"
+		if got[0].title != want || got[0].createdBy.Name != "main.main" {
+			t.Errorf("parseInput()[0] = %+v, want title %q created by main.main", got[0], want)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+// Command serrors finds stack traces in logs and prints them in a readable form.
+//
+// It reads the given files (or stdin if no files are given, or "-") and finds the following:
+//
+//   - the "stacktrace" blocks printed by github.com/Siroshun09/serrors/errorlogs
+//   - JSON lines that have "stacktrace" values, such as logs written by slog.JSONHandler
+//   - the output of unrecovered panics and goroutine dumps (runtime.Stack)
+//
+// Usage:
+//
+//	serrors [flags] [file...]
+//
+// The flags are:
+//
+//	-color auto|always|never
+//		Colorize the output. "auto" colorizes it if stdout is a terminal and NO_COLOR is not set.
+//	-module path
+//		Print only the frames of the module (or package) path.
+//	-all
+//		Print the frames of the runtime and the standard library, which are hidden by default.
+//	-group
+//		Group the stack traces by fingerprint, and print the count of each group.
+//	-source n
+//		Print n lines of source code around each frame if the file exists locally.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Siroshun09/serrors"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command and returns the exit code.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("serrors", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "usage: serrors [flags] [file...]")
+		flags.PrintDefaults()
+	}
+
+	colorMode := flags.String("color", "auto", "colorize the output: auto, always or never")
+	module := flags.String("module", "", "print only the frames of the module `path`")
+	all := flags.Bool("all", false, "print the frames of the runtime and the standard library")
+	group := flags.Bool("group", false, "group the stack traces by fingerprint")
+	sourceLines := flags.Int("source", 0, "print `n` lines of source code around each frame")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	color, err := useColor(*colorMode, stdout)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "serrors:", err)
+		return 2
+	}
+
+	data, err := readInputs(flags.Args(), stdin)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "serrors:", err)
+		return 1
+	}
+
+	traces := parseInput(data)
+	if len(traces) == 0 {
+		_, _ = fmt.Fprintln(stderr, "serrors: no stack traces found")
+		return 1
+	}
+
+	opt := renderOption{color: color, group: *group, sourceLines: *sourceLines}
+	if !*all {
+		opt.filters = append(opt.filters, serrors.WithoutRuntime(), serrors.WithoutStdlib())
+	}
+	if *module != "" {
+		opt.filters = append(opt.filters, serrors.OnlyModule(*module))
+	}
+
+	if err := newPrinter(stdout, opt).print(traces); err != nil {
+		_, _ = fmt.Fprintln(stderr, "serrors:", err)
+		return 1
+	}
+	return 0
+}
+
+func useColor(mode string, stdout io.Writer) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		f, ok := stdout.(*os.File)
+		if !ok {
+			return false, nil
+		}
+		info, err := f.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	default:
+		return false, fmt.Errorf("invalid -color value: %q (want auto, always or never)", mode)
+	}
+}
+
+// readInputs reads the files, or stdin if no files are given. "-" also means stdin.
+func readInputs(files []string, stdin io.Reader) ([]byte, error) {
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	var buf bytes.Buffer
+	for _, file := range files {
+		var data []byte
+		var err error
+		if file == "-" {
+			data, err = io.ReadAll(stdin)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		buf.Write(data)
+		if 0 < len(data) && data[len(data)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+)
+
+const errorlogsInput = "ERROR failed: a\n" +
+	"stacktrace\n" +
+	"example.com/app.find (/src/app/find.go:10)\n" +
+	"runtime.goexit (/usr/local/go/src/runtime/asm_amd64.s:1700)\n" +
+	"ERROR failed: b\n" +
+	"stacktrace\n" +
+	"example.com/app.load (/src/app/load.go:20)\n" +
+	"github.com/lib/retry.Do (/src/lib/retry.go:30)\n" +
+	"ERROR failed: a\n" +
+	"stacktrace\n" +
+	"example.com/app.find (/src/app/find.go:10)\n"
+
+func TestRun(t *testing.T) {
+	fingerprintA := serrors.StackTrace{{Name: "example.com/app.find"}}.Fingerprint()
+	fingerprintB := serrors.StackTrace{{Name: "example.com/app.load"}, {Name: "github.com/lib/retry.Do"}}.Fingerprint()
+
+	missing := filepath.Join(t.TempDir(), "nonexistent.log")
+	_, readErr := os.ReadFile(missing)
+
+	source := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(source, []byte("package main\n\nfunc main() {\n\tpanic(\"boom\")\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:     "default",
+			args:     []string{"-color", "never"},
+			stdin:    errorlogsInput,
+			wantCode: 0,
+			wantStdout: "ERROR failed: a\n" +
+				"  example.com/app.find (/src/app/find.go:10)\n" +
+				"  (1 frame hidden)\n" +
+				"\n" +
+				"ERROR failed: b\n" +
+				"  example.com/app.load (/src/app/load.go:20)\n" +
+				"  github.com/lib/retry.Do (/src/lib/retry.go:30)\n" +
+				"\n" +
+				"ERROR failed: a\n" +
+				"  example.com/app.find (/src/app/find.go:10)\n",
+		},
+		{
+			name:     "group and module",
+			args:     []string{"-color=never", "-group", "-module", "example.com/app"},
+			stdin:    errorlogsInput,
+			wantCode: 0,
+			wantStdout: "ERROR failed: a\n" +
+				"  2 occurrences, fingerprint: " + fingerprintA + "\n" +
+				"  example.com/app.find (/src/app/find.go:10)\n" +
+				"  (1 frame hidden)\n" +
+				"\n" +
+				"ERROR failed: b\n" +
+				"  1 occurrence, fingerprint: " + fingerprintB + "\n" +
+				"  example.com/app.load (/src/app/load.go:20)\n" +
+				"  (1 frame hidden)\n",
+		},
+		{
+			name:     "all",
+			args:     []string{"-color=never", "-all", "-"},
+			stdin:    "stacktrace\nruntime.goexit (/usr/local/go/src/runtime/asm_amd64.s:1700)\n",
+			wantCode: 0,
+			wantStdout: "(unknown error)\n" +
+				"  runtime.goexit (/usr/local/go/src/runtime/asm_amd64.s:1700)\n",
+		},
+		{
+			name:     "truncated",
+			args:     []string{"-color=never"},
+			stdin:    "ERROR failed\nstacktrace\nexample.com/app.find (/src/app/find.go:10)\n... 1 more frame\n",
+			wantCode: 0,
+			wantStdout: "ERROR failed\n" +
+				"  example.com/app.find (/src/app/find.go:10)\n" +
+				"  ... 1 more frame\n",
+		},
+		{
+			name:     "color",
+			args:     []string{"-color=always"},
+			stdin:    "ERROR failed\nstacktrace\nexample.com/app.find (/src/app/find.go:10)\n",
+			wantCode: 0,
+			wantStdout: "\x1b[1;31mERROR failed\x1b[0m\n" +
+				"  \x1b[36mexample.com/app.find\x1b[0m \x1b[2m(/src/app/find.go:10)\x1b[0m\n",
+		},
+		{
+			name:     "source",
+			args:     []string{"-color=never", "-source", "1"},
+			stdin:    "panic: boom\n\ngoroutine 1 [running]:\nmain.main()\n\t" + source + ":4 +0x18\nexit status 2\n",
+			wantCode: 0,
+			wantStdout: "panic: boom (goroutine 1 [running])\n" +
+				"  main.main (" + source + ":4)\n" +
+				"      3 | func main() {\n" +
+				"    > 4 | \tpanic(\"boom\")\n" +
+				"      5 | }\n",
+		},
+		{
+			name:     "source not found",
+			args:     []string{"-color=never", "-source", "2"},
+			stdin:    "stacktrace\nexample.com/app.find (/nonexistent/find.go:10)\n",
+			wantCode: 0,
+			wantStdout: "(unknown error)\n" +
+				"  example.com/app.find (/nonexistent/find.go:10)\n",
+		},
+		{
+			name:       "no stack traces",
+			args:       []string{"-color=never"},
+			stdin:      "hello\n",
+			wantCode:   1,
+			wantStderr: "serrors: no stack traces found\n",
+		},
+		{
+			name:       "invalid color",
+			args:       []string{"-color=sometimes"},
+			wantCode:   2,
+			wantStderr: "serrors: invalid -color value: \"sometimes\" (want auto, always or never)\n",
+		},
+		{
+			name:       "file not found",
+			args:       []string{"-color=never", missing},
+			wantCode:   1,
+			wantStderr: "serrors: failed to read " + missing + ": " + readErr.Error() + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("run() = %d, want %d (stderr: %s)", code, tt.wantCode, stderr.String())
+			}
+			if stdout.String() != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.wantStdout)
+			}
+			if !strings.HasPrefix(stderr.String(), tt.wantStderr) || (tt.wantStderr == "" && stderr.Len() != 0) {
+				t.Errorf("stderr = %q, want %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestRun_files(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.log")
+	second := filepath.Join(dir, "second.log")
+	if err := os.WriteFile(first, []byte("ERROR a\nstacktrace\nexample.com/app.a (/src/app/a.go:1)"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte("ERROR b\nstacktrace\nexample.com/app.b (/src/app/b.go:2)\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-color=never", first, second}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("run() = %d, stderr: %s", code, stderr.String())
+	}
+	want := "ERROR a\n  example.com/app.a (/src/app/a.go:1)\n\nERROR b\n  example.com/app.b (/src/app/b.go:2)\n"
+	if stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Siroshun09/serrors"
+)
+
+const (
+	colorReset   = "\x1b[0m"
+	colorBoldRed = "\x1b[1;31m"
+	colorCyan    = "\x1b[36m"
+	colorYellow  = "\x1b[33m"
+	colorDim     = "\x1b[2m"
+)
+
+// renderOption is the option for printer.
+type renderOption struct {
+	// color is whether to colorize the output with ANSI escape sequences.
+	color bool
+	// filters are the filters applied to the frames. The hidden frames are counted.
+	filters []serrors.FrameFilter
+	// group is whether to group the traces by fingerprint.
+	group bool
+	// sourceLines is the number of source lines printed around each frame. If 0, source lines are not printed.
+	sourceLines int
+}
+
+type printer struct {
+	w   io.Writer
+	opt renderOption
+}
+
+func newPrinter(w io.Writer, opt renderOption) *printer {
+	return &printer{w: w, opt: opt}
+}
+
+// group is the traces that have the same fingerprint.
+type group struct {
+	fingerprint string
+	traces      []trace
+}
+
+// groupTraces groups traces by the fingerprint of their StackTraces, the most frequent group first.
+func groupTraces(traces []trace) []group {
+	var groups []group
+	index := map[string]int{}
+	for _, t := range traces {
+		fingerprint := t.stackTrace.Fingerprint()
+		if i, ok := index[fingerprint]; ok {
+			groups[i].traces = append(groups[i].traces, t)
+			continue
+		}
+		index[fingerprint] = len(groups)
+		groups = append(groups, group{fingerprint: fingerprint, traces: []trace{t}})
+	}
+
+	slices.SortStableFunc(groups, func(a, b group) int {
+		return len(b.traces) - len(a.traces)
+	})
+	return groups
+}
+
+func (p *printer) print(traces []trace) error {
+	var b strings.Builder
+	if p.opt.group {
+		for i, g := range groupTraces(traces) {
+			if 0 < i {
+				b.WriteString("\n")
+			}
+			p.writeTitle(&b, g.traces[0].title)
+			p.writeColored(&b, colorDim, "  "+plural(len(g.traces), "occurrence")+", fingerprint: "+g.fingerprint)
+			b.WriteString("\n")
+			p.writeTrace(&b, g.traces[0])
+		}
+	} else {
+		for i, t := range traces {
+			if 0 < i {
+				b.WriteString("\n")
+			}
+			p.writeTitle(&b, t.title)
+			p.writeTrace(&b, t)
+		}
+	}
+
+	_, err := io.WriteString(p.w, b.String())
+	return err
+}
+
+// plural returns the count n with the noun, like "1 frame" or "2 frames".
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(n) + " " + noun + "s"
+}
+
+func (p *printer) writeTitle(b *strings.Builder, title string) {
+	if title == "" {
+		title = "(unknown error)"
+	}
+	p.writeColored(b, colorBoldRed, title)
+	b.WriteString("\n")
+}
+
+func (p *printer) writeTrace(b *strings.Builder, t trace) {
+	stackTrace := t.stackTrace
+	if 0 < len(p.opt.filters) {
+		stackTrace = stackTrace.Filter(p.opt.filters...)
+	}
+
+	for _, funcInfo := range stackTrace {
+		p.writeFrame(b, "", funcInfo)
+	}
+	if t.omitted != 0 {
+		p.writeColored(b, colorDim, "  "+serrors.FormatOmittedFrames(t.omitted))
+		b.WriteString("\n")
+	}
+	if hidden := len(t.stackTrace) - len(stackTrace); 0 < hidden {
+		p.writeColored(b, colorDim, "  ("+plural(hidden, "frame")+" hidden)")
+		b.WriteString("\n")
+	}
+	if t.createdBy != (serrors.FuncInfo{}) {
+		p.writeFrame(b, "created by ", t.createdBy)
+	}
+}
+
+func (p *printer) writeFrame(b *strings.Builder, prefix string, funcInfo serrors.FuncInfo) {
+	b.WriteString("  " + prefix)
+	p.writeColored(b, colorCyan, funcInfo.Name)
+	b.WriteString(" ")
+	p.writeColored(b, colorDim, "("+funcInfo.File+":"+strconv.Itoa(funcInfo.Line)+")")
+	b.WriteString("\n")
+
+	if 0 < p.opt.sourceLines {
+		p.writeSource(b, funcInfo)
+	}
+}
+
+// writeSource writes the source lines around funcInfo, if the file is available locally.
+func (p *printer) writeSource(b *strings.Builder, funcInfo serrors.FuncInfo) {
+	source := funcInfo.Source(p.opt.sourceLines)
+	if len(source) == 0 {
+		return
+	}
+
+	width := len(strconv.Itoa(source[len(source)-1].Number))
+	for _, line := range source {
+		text := fmt.Sprintf("%*d | %s", width, line.Number, line.Text)
+		if line.Current {
+			b.WriteString("    > ")
+			p.writeColored(b, colorYellow, text)
+		} else {
+			b.WriteString("      ")
+			p.writeColored(b, colorDim, text)
+		}
+		b.WriteString("\n")
+	}
+}
+
+func (p *printer) writeColored(b *strings.Builder, color string, s string) {
+	if !p.opt.color {
+		b.WriteString(s)
+		return
+	}
+	b.WriteString(color + s + colorReset)
+}
@@ -0,0 +1,81 @@
+package serrors
+
+import (
+	"iter"
+	"slices"
+)
+
+// StackTraceEntry is an error and its StackTrace returned by GetAllStackTraces and GetStackTraceEntries.
+type StackTraceEntry struct {
+	// Err is the error that the StackTrace is attached to, in the same way as GetStackTraces.
+	Err error
+	// StackTrace is the StackTrace attached to Err.
+	StackTrace StackTrace
+	// Omitted is the number of frames omitted from the end of StackTrace because of the max depth (see GetOmittedFrames).
+	// FormatOmittedFrames formats it in the same way as %+v.
+	Omitted int
+	// Depth is the number of Unwrap calls from the given error to the error that has the StackTrace.
+	Depth int
+	// Path is the position of the error that has the StackTrace in the error tree.
+	//
+	// Each element is the index of the error returned by Unwrap at each depth:
+	// 0 for Unwrap() error, and the index in the returned slice for Unwrap() []error.
+	// The length of Path is equal to Depth.
+	Path []int
+}
+
+// GetAllStackTraces returns a sequence of every StackTrace in the error tree.
+//
+// Unlike GetStackTraces, this function does not stop at the first error that has a StackTrace,
+// but continues into the wrapped error, so the StackTraces of inner errors are also returned.
+// The entries are returned in depth-first order.
+//
+// The StackTrace of the goroutine that started the goroutine returning the error (see Go)
+// is returned as a separate entry instead of being appended to the other StackTraces.
+func GetAllStackTraces(err error) iter.Seq[StackTraceEntry] {
+	return func(yield func(StackTraceEntry) bool) {
+		yieldAllStackTraces(err, nil, yield)
+	}
+}
+
+func yieldAllStackTraces(err error, path []int, yield func(StackTraceEntry) bool) bool {
+	if err == nil {
+		return true
+	}
+
+	var wrapped error
+	var stackTrace StackTrace
+	var omitted int
+	switch x := err.(type) {
+	case *stackTraceError:
+		wrapped, stackTrace, omitted = x.err, x.getStackTrace(), x.omitted
+	case *goroutineError:
+		wrapped, stackTrace, omitted = x.err, x.getCreatedBy(), x.omitted
+	}
+
+	if wrapped != nil && !yield(newStackTraceEntry(wrapped, stackTrace, omitted, path)) {
+		return false
+	}
+
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		return yieldAllStackTraces(x.Unwrap(), append(path, 0), yield)
+	case interface{ Unwrap() []error }:
+		for i, err := range x.Unwrap() {
+			if !yieldAllStackTraces(err, append(path, i), yield) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func newStackTraceEntry(err error, stackTrace StackTrace, omitted int, path []int) StackTraceEntry {
+	return StackTraceEntry{
+		Err:        err,
+		StackTrace: stackTrace,
+		Omitted:    omitted,
+		Depth:      len(path),
+		Path:       slices.Clone(path),
+	}
+}
@@ -0,0 +1,107 @@
+package serrors
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestGetAllStackTraces(t *testing.T) {
+	stackTrace1 := StackTrace{{Name: "Test1", File: "test.go", Line: 1}}
+	stackTrace2 := StackTrace{{Name: "Test2", File: "test.go", Line: 2}}
+	stackTrace3 := StackTrace{{Name: "Test3", File: "test.go", Line: 3}}
+	base1 := errors.New("test1")
+	base2 := errors.New("test2")
+	inner1 := &stackTraceError{err: base1, stackTrace: stackTrace1}
+	inner2 := &stackTraceError{err: base2, stackTrace: stackTrace2}
+	wrapped := fmt.Errorf("wrap: %w", inner1)
+	outer := &stackTraceError{err: wrapped, stackTrace: stackTrace3}
+	truncated := &stackTraceError{err: base1, omitted: 2, stackTrace: stackTrace1}
+
+	tests := []struct {
+		name string
+		err  error
+		want []StackTraceEntry
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "no stack trace",
+			err:  fmt.Errorf("wrap: %w", errors.New("test")),
+			want: nil,
+		},
+		{
+			name: "single stack trace",
+			err:  inner1,
+			want: []StackTraceEntry{
+				{Err: base1, StackTrace: stackTrace1, Depth: 0, Path: nil},
+			},
+		},
+		{
+			name: "nested stack traces",
+			err:  outer,
+			want: []StackTraceEntry{
+				{Err: wrapped, StackTrace: stackTrace3, Depth: 0, Path: nil},
+				{Err: base1, StackTrace: stackTrace1, Depth: 2, Path: []int{0, 0}},
+			},
+		},
+		{
+			name: "joined errors",
+			err:  fmt.Errorf("wrap: %w", errors.Join(outer, errors.New("plain"), inner2)),
+			want: []StackTraceEntry{
+				{Err: wrapped, StackTrace: stackTrace3, Depth: 2, Path: []int{0, 0}},
+				{Err: base1, StackTrace: stackTrace1, Depth: 4, Path: []int{0, 0, 0, 0}},
+				{Err: base2, StackTrace: stackTrace2, Depth: 2, Path: []int{0, 2}},
+			},
+		},
+		{
+			name: "goroutine error",
+			err:  &goroutineError{err: inner2, createdBy: stackTrace3},
+			want: []StackTraceEntry{
+				{Err: inner2, StackTrace: stackTrace3, Depth: 0, Path: nil},
+				{Err: base2, StackTrace: stackTrace2, Depth: 1, Path: []int{0}},
+			},
+		},
+		{
+			name: "omitted frames",
+			err:  &goroutineError{err: truncated, omitted: -1, createdBy: stackTrace3},
+			want: []StackTraceEntry{
+				{Err: truncated, StackTrace: stackTrace3, Omitted: -1, Depth: 0, Path: nil},
+				{Err: base1, StackTrace: stackTrace1, Omitted: 2, Depth: 1, Path: []int{0}},
+			},
+		},
+		{
+			name: "contains nil error in multiple errors",
+			err:  &multipleErrorsWrapper{errs: []error{nil, inner1}},
+			want: []StackTraceEntry{
+				{Err: base1, StackTrace: stackTrace1, Depth: 1, Path: []int{1}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []StackTraceEntry
+			for entry := range GetAllStackTraces(tt.err) {
+				got = append(got, entry)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetAllStackTraces() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("break iterator", func(t *testing.T) {
+		count := 0
+		for range GetAllStackTraces(errors.Join(outer, inner2)) {
+			count++
+			break
+		}
+		if count != 1 {
+			t.Errorf("iterated %d times, want 1", count)
+		}
+	})
+}
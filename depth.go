@@ -0,0 +1,81 @@
+package serrors
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+// DefaultMaxStackDepth is the max number of frames captured in a StackTrace by default.
+const DefaultMaxStackDepth = 64
+
+// maxStackDepth holds the value set by SetMaxStackDepth.
+// 0 means DefaultMaxStackDepth, and a negative value means that the depth is not limited.
+var maxStackDepth atomic.Int64
+
+// countOmittedFrames holds the value set by SetCountOmittedFrames.
+var countOmittedFrames atomic.Bool
+
+// SetMaxStackDepth sets the max number of frames captured in the StackTraces of the errors created after this call.
+//
+// If depth is 0 or negative, the depth is not limited, and the whole stack is captured.
+// Deeper stacks are truncated, and GetOmittedFrames reports that frames are omitted (see SetCountOmittedFrames).
+func SetMaxStackDepth(depth int) {
+	if depth <= 0 {
+		maxStackDepth.Store(-1)
+		return
+	}
+	maxStackDepth.Store(int64(depth))
+}
+
+// MaxStackDepth returns the max number of frames set by SetMaxStackDepth.
+//
+// If the depth is not limited, MaxStackDepth returns 0.
+func MaxStackDepth() int {
+	switch depth := maxStackDepth.Load(); {
+	case depth == 0:
+		return DefaultMaxStackDepth
+	case depth < 0:
+		return 0
+	default:
+		return int(depth)
+	}
+}
+
+// SetCountOmittedFrames sets whether to count the frames omitted from the StackTraces of the errors created after this call.
+//
+// By default, the frames are not counted, because counting them walks the whole stack every time an error is created.
+// Then, the number of the omitted frames is -1, and it is printed as "... more frames".
+// The StackTraces of recovered panics always have the number, since the whole stack is captured to find the panic site.
+func SetCountOmittedFrames(count bool) {
+	countOmittedFrames.Store(count)
+}
+
+// GetOmittedFrames returns the number of frames omitted from the end of the StackTrace returned by GetAttachedStackTrace
+// because of the max depth set by SetMaxStackDepth.
+//
+// If the StackTrace is truncated but the omitted frames are not counted (see SetCountOmittedFrames), GetOmittedFrames returns -1.
+// If the StackTrace is not truncated or err does not have a StackTrace, GetOmittedFrames returns 0.
+// GetStackTraceEntries returns the number for every StackTrace in the error chain.
+func GetOmittedFrames(err error) int {
+	serr := getStackTraceError(err)
+	if serr == nil {
+		return 0
+	}
+	return serr.omitted
+}
+
+// FormatOmittedFrames formats the number of frames omitted from a truncated StackTrace as "... N more frames".
+//
+// If n is negative (the frames are not counted), it returns "... more frames". If n is 0, it returns an empty string.
+func FormatOmittedFrames(n int) string {
+	switch {
+	case n < 0:
+		return "... more frames"
+	case n == 0:
+		return ""
+	case n == 1:
+		return "... 1 more frame"
+	default:
+		return "... " + strconv.Itoa(n) + " more frames"
+	}
+}
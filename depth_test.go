@@ -0,0 +1,228 @@
+package serrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+const depthTestFuncName = "github.com/Siroshun09/serrors.recurse"
+
+// recurse calls fn after n more nested calls of recurse.
+//
+//go:noinline
+func recurse(n int, fn func() error) error {
+	if n == 0 {
+		return fn()
+	}
+	return recurse(n-1, fn)
+}
+
+func setMaxStackDepth(t *testing.T, depth int) {
+	t.Helper()
+
+	prev := MaxStackDepth()
+	SetMaxStackDepth(depth)
+	t.Cleanup(func() { SetMaxStackDepth(prev) })
+}
+
+func setCountOmittedFrames(t *testing.T, count bool) {
+	t.Helper()
+
+	prev := countOmittedFrames.Load()
+	SetCountOmittedFrames(count)
+	t.Cleanup(func() { SetCountOmittedFrames(prev) })
+}
+
+func TestSetMaxStackDepth(t *testing.T) {
+	tests := []struct {
+		name          string
+		depth         int
+		count         bool
+		calls         int
+		wantLen       int
+		wantTruncated bool
+	}{
+		{name: "default", depth: DefaultMaxStackDepth, calls: 100, wantLen: DefaultMaxStackDepth, wantTruncated: true},
+		{name: "small", depth: 5, calls: 10, wantLen: 5, wantTruncated: true},
+		{name: "large", depth: 200, calls: 300, wantLen: 200, wantTruncated: true},
+		{name: "counted", depth: 5, count: true, calls: 10, wantLen: 5, wantTruncated: true},
+		{name: "counted deep", depth: 5, count: true, calls: 1000, wantLen: 5, wantTruncated: true},
+		{name: "not truncated", depth: 200, calls: 10},
+		{name: "unlimited", depth: 0, calls: 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setMaxStackDepth(t, tt.depth)
+			setCountOmittedFrames(t, tt.count)
+
+			err := recurse(tt.calls, func() error { return New("test") })
+			stackTrace := GetStackTrace(err)
+			omitted := GetOmittedFrames(err)
+
+			recursions := 0
+			for _, funcInfo := range stackTrace {
+				if funcInfo.Name == depthTestFuncName {
+					recursions++
+				}
+			}
+
+			if (omitted != 0) != tt.wantTruncated {
+				t.Fatalf("GetOmittedFrames() = %d, want truncated: %v\n%s", omitted, tt.wantTruncated, stackTrace)
+			}
+			if !tt.wantTruncated {
+				if recursions != tt.calls+1 {
+					t.Errorf("recursions = %d, want %d", recursions, tt.calls+1)
+				}
+				return
+			}
+
+			if len(stackTrace) != tt.wantLen {
+				t.Errorf("len(StackTrace) = %d, want %d", len(stackTrace), tt.wantLen)
+			}
+			if !tt.count {
+				if omitted != -1 {
+					t.Errorf("GetOmittedFrames() = %d, want -1", omitted)
+				}
+				return
+			}
+			// all frames above the recursion are omitted, so the omitted frames and the captured recursions make up the whole stack
+			if omittedRecursions := tt.calls + 1 - recursions; omitted <= omittedRecursions {
+				t.Errorf("GetOmittedFrames() = %d, want more than %d omitted recursions", omitted, omittedRecursions)
+			}
+		})
+	}
+}
+
+func TestMaxStackDepth(t *testing.T) {
+	tests := []struct {
+		name  string
+		depth int
+		want  int
+	}{
+		{name: "positive", depth: 10, want: 10},
+		{name: "zero", depth: 0, want: 0},
+		{name: "negative", depth: -1, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setMaxStackDepth(t, tt.depth)
+			if got := MaxStackDepth(); got != tt.want {
+				t.Errorf("MaxStackDepth() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewWithSkip(t *testing.T) {
+	newError := func(skip int) error {
+		return NewWithSkip(skip, "test")
+	}
+
+	tests := []struct {
+		name string
+		skip int
+		want string
+	}{
+		{name: "zero", skip: 0, want: "github.com/Siroshun09/serrors.TestNewWithSkip.func1"},
+		{name: "one", skip: 1, want: "github.com/Siroshun09/serrors.TestNewWithSkip.func2"},
+		{name: "negative", skip: -1, want: "github.com/Siroshun09/serrors.TestNewWithSkip.func1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newError(tt.skip)
+			if err.Error() != "test" {
+				t.Errorf("Error() = %q, want %q", err.Error(), "test")
+			}
+			stackTrace, ok := GetAttachedStackTrace(err)
+			if !ok || len(stackTrace) == 0 || stackTrace[0].Name != tt.want {
+				t.Errorf("StackTrace = %v, want to start with %s", stackTrace, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithStackTraceSkip(t *testing.T) {
+	wrap := func(err error) error {
+		return WithStackTraceSkip(1, err)
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		err := wrap(errors.New("test"))
+		stackTrace, ok := GetAttachedStackTrace(err)
+		if want := "github.com/Siroshun09/serrors.TestWithStackTraceSkip.func2"; !ok || len(stackTrace) == 0 || stackTrace[0].Name != want {
+			t.Errorf("StackTrace = %v, want to start with %s", stackTrace, want)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		if err := wrap(nil); err != nil {
+			t.Errorf("WithStackTraceSkip() = %v, want nil", err)
+		}
+	})
+
+	t.Run("same", func(t *testing.T) {
+		err := New("test")
+		if got := wrap(err); got != err {
+			t.Errorf("WithStackTraceSkip() = %v, want the same error", got)
+		}
+	})
+}
+
+func TestGetOmittedFrames(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: 0},
+		{name: "no stack trace", err: errors.New("test"), want: 0},
+		{name: "not truncated", err: &stackTraceError{err: errors.New("test")}, want: 0},
+		{name: "truncated", err: fmt.Errorf("wrap: %w", &stackTraceError{err: errors.New("test"), omitted: 3}), want: 3},
+		{name: "unknown", err: &stackTraceError{err: errors.New("test"), omitted: -1}, want: -1},
+		{name: "created by", err: &goroutineError{err: errors.New("test"), omitted: 3}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetOmittedFrames(tt.err); got != tt.want {
+				t.Errorf("GetOmittedFrames() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatch_maxStackDepth(t *testing.T) {
+	setMaxStackDepth(t, 3)
+
+	err := recurse(10, func() error {
+		return Catch(func() error { panic("test") })
+	})
+
+	stackTrace := GetStackTrace(err)
+	if want := "github.com/Siroshun09/serrors.TestCatch_maxStackDepth.func1.1"; len(stackTrace) == 0 || stackTrace[0].Name != want {
+		t.Errorf("StackTrace = %v, want to start with %s", stackTrace, want)
+	}
+	if len(stackTrace) != 3 {
+		t.Errorf("len(StackTrace) = %d, want 3", len(stackTrace))
+	}
+	if omitted := GetOmittedFrames(err); omitted <= 10 {
+		t.Errorf("GetOmittedFrames() = %d, want more than 10", omitted)
+	}
+}
+
+func TestFormatOmittedFrames(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{n: 0, want: ""},
+		{n: 1, want: "... 1 more frame"},
+		{n: 3, want: "... 3 more frames"},
+		{n: -1, want: "... more frames"},
+	}
+	for _, tt := range tests {
+		if got := FormatOmittedFrames(tt.n); got != tt.want {
+			t.Errorf("FormatOmittedFrames(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package serrors
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Goroutine is a goroutine parsed from a goroutine dump by ParseGoroutineDump or ParsePanic.
+type Goroutine struct {
+	// ID is the goroutine ID.
+	ID int64
+	// State is the state of the goroutine, such as "running" or "chan receive".
+	State string
+	// WaitTime is how long the goroutine has been blocked, which is printed by the runtime in minutes.
+	//
+	// If the dump does not have a wait time, WaitTime is 0.
+	WaitTime time.Duration
+	// LockedToThread reports whether the goroutine is locked to an OS thread by runtime.LockOSThread.
+	LockedToThread bool
+	// StackTrace is the stack trace of the goroutine, the innermost call first.
+	StackTrace StackTrace
+	// Inlined contains the indices of the frames in StackTrace that are inlined (printed with "(...)" as their arguments).
+	Inlined []int
+	// ElidedFrames is the number of frames elided from StackTrace by "...N frames elided...".
+	//
+	// If the dump says "...additional frames elided..." without the number, ElidedFrames is -1.
+	ElidedFrames int
+	// CreatedBy is the frame of the go statement that created the goroutine.
+	//
+	// If the dump does not have "created by", CreatedBy is the zero FuncInfo.
+	CreatedBy FuncInfo
+	// CreatorID is the ID of the goroutine that created the goroutine.
+	//
+	// If the dump does not have "in goroutine N" (Go 1.21 or earlier), CreatorID is 0.
+	CreatorID int64
+}
+
+// Panic is the output of an unrecovered panic or a fatal error parsed by ParsePanic.
+type Panic struct {
+	// Message is the message of the panic, such as "panic: boom" or "fatal error: all goroutines are asleep - deadlock!".
+	//
+	// If the panic is raised while panicking, Message has multiple lines, like "panic: first [recovered]\n\tpanic: second".
+	Message string
+	// Goroutines is the goroutines in the output. The first goroutine is usually the one that panicked.
+	Goroutines []Goroutine
+}
+
+// ParsePanic parses the output of an unrecovered panic or a fatal error.
+//
+// The lines before "panic: " or "fatal error: " are ignored, so output can contain other logs.
+// If the output does not have goroutines (e.g. GOTRACEBACK=none), Panic.Goroutines is empty.
+func ParsePanic(output []byte) (*Panic, error) {
+	lines := splitLines(output)
+
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "panic: ") || strings.HasPrefix(line, "fatal error: ") {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil, errors.New("panic message not found")
+	}
+
+	end := start + 1
+	for end < len(lines) && lines[end] != "" && !strings.HasPrefix(lines[end], "goroutine ") {
+		end++
+	}
+
+	goroutines, err := parseGoroutines(lines[end:], end)
+	if err != nil {
+		return nil, err
+	}
+	return &Panic{Message: strings.Join(lines[start:end], "\n"), Goroutines: goroutines}, nil
+}
+
+// ParseGoroutineDump parses the goroutines in dump, such as the output of runtime.Stack(buf, true) or debug.Stack.
+//
+// The lines outside the goroutines are ignored, so dump can contain other logs.
+func ParseGoroutineDump(dump []byte) ([]Goroutine, error) {
+	goroutines, err := parseGoroutines(splitLines(dump), 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(goroutines) == 0 {
+		return nil, errors.New("no goroutines found")
+	}
+	return goroutines, nil
+}
+
+func splitLines(b []byte) []string {
+	text := string(bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n")))
+	return strings.Split(strings.TrimRight(text, "\n"), "\n")
+}
+
+// parseGoroutines parses the goroutines in lines. offset is the index of lines[0], which is used in error messages.
+func parseGoroutines(lines []string, offset int) ([]Goroutine, error) {
+	var goroutines []Goroutine
+	for i := 0; i < len(lines); i++ {
+		g, ok := parseGoroutineHeader(lines[i])
+		if !ok {
+			continue
+		}
+
+		n, err := parseGoroutineBody(&g, lines[i+1:], offset+i+1)
+		if err != nil {
+			return nil, err
+		}
+		goroutines = append(goroutines, g)
+		i += n
+	}
+	return goroutines, nil
+}
+
+// parseGoroutineHeader parses a line like "goroutine 1 [running]:" or "goroutine 1 gp=0x... m=0 mp=0x... [chan receive, 5 minutes]:".
+func parseGoroutineHeader(line string) (Goroutine, bool) {
+	rest, ok := strings.CutPrefix(line, "goroutine ")
+	if !ok || !strings.HasSuffix(rest, "]:") {
+		return Goroutine{}, false
+	}
+
+	idEnd := strings.IndexByte(rest, ' ')
+	stateStart := strings.IndexByte(rest, '[')
+	if idEnd < 0 || stateStart < idEnd {
+		return Goroutine{}, false
+	}
+	id, err := strconv.ParseInt(rest[:idEnd], 10, 64)
+	if err != nil || id <= 0 {
+		return Goroutine{}, false
+	}
+
+	g := Goroutine{ID: id}
+	for i, part := range strings.Split(rest[stateStart+1:len(rest)-2], ", ") {
+		switch {
+		case i == 0:
+			g.State = part
+		case part == "locked to thread":
+			g.LockedToThread = true
+		case strings.HasSuffix(part, " minutes") || strings.HasSuffix(part, " minute"):
+			minutes, err := strconv.Atoi(part[:strings.IndexByte(part, ' ')])
+			if err == nil {
+				g.WaitTime = time.Duration(minutes) * time.Minute
+			}
+		}
+	}
+	return g, true
+}
+
+// parseGoroutineBody parses the frames of g from lines, and returns the number of consumed lines.
+// offset is the index of lines[0], which is used in error messages.
+func parseGoroutineBody(g *Goroutine, lines []string, offset int) (int, error) {
+	i := 0
+	for i < len(lines) {
+		line := lines[i]
+		switch {
+		case line == "...additional frames elided...":
+			g.ElidedFrames = -1
+			i++
+			continue
+		case strings.HasPrefix(line, "...") && strings.HasSuffix(line, " frames elided..."):
+			n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(line, "..."), " frames elided..."))
+			if err != nil {
+				return 0, fmt.Errorf("line %d: invalid elided frames: %q", offset+i+1, line)
+			}
+			if 0 <= g.ElidedFrames {
+				g.ElidedFrames += n
+			}
+			i++
+			continue
+		case strings.HasPrefix(line, "\t"):
+			// "goroutine running on other thread; stack unavailable" and other notes from the runtime
+			i++
+			continue
+		case line == "" || strings.HasPrefix(line, "goroutine "):
+			return i, nil
+		}
+
+		if i+1 == len(lines) || !strings.HasPrefix(lines[i+1], "\t") {
+			if len(g.StackTrace) == 0 && g.CreatedBy == (FuncInfo{}) {
+				return 0, fmt.Errorf("line %d: missing file and line of %q", offset+i+1, line)
+			}
+			// the end of the goroutine, such as "exit status 2"
+			return i, nil
+		}
+
+		file, lineNumber, ok := parseFrameLocation(lines[i+1])
+		if !ok {
+			return 0, fmt.Errorf("line %d: invalid file and line: %q", offset+i+2, lines[i+1])
+		}
+
+		if creator, ok := strings.CutPrefix(line, "created by "); ok {
+			name, goroutine, found := strings.Cut(creator, " in goroutine ")
+			if found {
+				id, err := strconv.ParseInt(goroutine, 10, 64)
+				if err != nil {
+					return 0, fmt.Errorf("line %d: invalid goroutine ID: %q", offset+i+1, line)
+				}
+				g.CreatorID = id
+			}
+			g.CreatedBy = FuncInfo{Name: name, File: file, Line: lineNumber}
+			i += 2
+			continue
+		}
+
+		name, inlined, ok := parseFrameFunc(line)
+		if !ok {
+			return 0, fmt.Errorf("line %d: invalid function call: %q", offset+i+1, line)
+		}
+		if inlined {
+			g.Inlined = append(g.Inlined, len(g.StackTrace))
+		}
+		g.StackTrace = append(g.StackTrace, FuncInfo{Name: name, File: file, Line: lineNumber})
+		i += 2
+	}
+	return i, nil
+}
+
+// parseFrameFunc parses a line like "main.(*Server).handle(0x1, {0x2, 0x3})" or "main.inlined(...)".
+func parseFrameFunc(line string) (name string, inlined bool, ok bool) {
+	if !strings.HasSuffix(line, ")") {
+		return "", false, false
+	}
+	argsStart := strings.LastIndexByte(line, '(')
+	if argsStart <= 0 {
+		return "", false, false
+	}
+	return line[:argsStart], line[argsStart:] == "(...)", true
+}
+
+// parseFrameLocation parses a line like "\t/path/to/file.go:10 +0x25 fp=0x... sp=0x... pc=0x...".
+func parseFrameLocation(line string) (file string, lineNumber int, ok bool) {
+	location := strings.TrimPrefix(line, "\t")
+	if i := strings.Index(location, " +0x"); 0 <= i {
+		location = location[:i]
+	} else if i := strings.Index(location, " fp=0x"); 0 <= i {
+		location = location[:i]
+	}
+
+	lineStart := strings.LastIndexByte(location, ':')
+	if lineStart <= 0 {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(location[lineStart+1:])
+	if err != nil || n < 0 {
+		return "", 0, false
+	}
+	return location[:lineStart], n, true
+}
@@ -0,0 +1,328 @@
+package serrors
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func readDump(t testing.TB, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "dump", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParsePanic(t *testing.T) {
+	tests := []struct {
+		name           string
+		file           string
+		output         string
+		wantMessage    string
+		wantGoroutines int
+		wantFirst      Goroutine
+	}{
+		{
+			name:           "goroutine",
+			file:           "panic.txt",
+			wantMessage:    "panic: runtime error: index out of range [3] with length 2",
+			wantGoroutines: 5,
+			wantFirst: Goroutine{
+				ID:    9,
+				State: "running",
+				StackTrace: StackTrace{
+					{Name: "main.inlined", File: "/tmp/dumpgen/main.go", Line: 29},
+					{Name: "main.main.func2", File: "/tmp/dumpgen/main.go", Line: 51},
+				},
+				Inlined:   []int{0},
+				CreatedBy: FuncInfo{Name: "main.main", File: "/tmp/dumpgen/main.go", Line: 49},
+				CreatorID: 1,
+			},
+		},
+		{
+			name:           "recovered",
+			file:           "recovered.txt",
+			wantMessage:    "panic: first [recovered]\n\tpanic: again: first",
+			wantGoroutines: 1,
+			wantFirst: Goroutine{
+				ID:    1,
+				State: "running",
+				StackTrace: StackTrace{
+					{Name: "main.main.func3", File: "/tmp/dumpgen/main.go", Line: 59},
+					{Name: "panic", File: "/usr/local/go/src/runtime/panic.go", Line: 859},
+					{Name: "main.main", File: "/tmp/dumpgen/main.go", Line: 61},
+				},
+			},
+		},
+		{
+			name:           "fatal error",
+			output:         "log line\nfatal error: all goroutines are asleep - deadlock!\n\ngoroutine 1 [chan receive]:\nmain.main()\n\t/app/main.go:5 +0x18\nexit status 2\n",
+			wantMessage:    "fatal error: all goroutines are asleep - deadlock!",
+			wantGoroutines: 1,
+			wantFirst: Goroutine{
+				ID:         1,
+				State:      "chan receive",
+				StackTrace: StackTrace{{Name: "main.main", File: "/app/main.go", Line: 5}},
+			},
+		},
+		{
+			name:           "no goroutines",
+			output:         "panic: boom\r\n",
+			wantMessage:    "panic: boom",
+			wantGoroutines: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := []byte(tt.output)
+			if tt.file != "" {
+				output = readDump(t, tt.file)
+			}
+
+			got, err := ParsePanic(output)
+			if err != nil {
+				t.Fatalf("ParsePanic() error = %v", err)
+			}
+			if got.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", got.Message, tt.wantMessage)
+			}
+			if len(got.Goroutines) != tt.wantGoroutines {
+				t.Fatalf("len(Goroutines) = %d, want %d", len(got.Goroutines), tt.wantGoroutines)
+			}
+			if 0 < tt.wantGoroutines && !reflect.DeepEqual(got.Goroutines[0], tt.wantFirst) {
+				t.Errorf("Goroutines[0] = %+v, want %+v", got.Goroutines[0], tt.wantFirst)
+			}
+		})
+	}
+
+	t.Run("not a panic", func(t *testing.T) {
+		if _, err := ParsePanic(readDump(t, "stack.txt")); err == nil {
+			t.Error("ParsePanic() error = nil, want error")
+		}
+	})
+}
+
+func TestParseGoroutineDump(t *testing.T) {
+	t.Run("runtime.Stack", func(t *testing.T) {
+		got, err := ParseGoroutineDump(readDump(t, "stack.txt"))
+		if err != nil {
+			t.Fatalf("ParseGoroutineDump() error = %v", err)
+		}
+
+		want := []Goroutine{
+			{
+				ID:         1,
+				State:      "running",
+				StackTrace: StackTrace{{Name: "main.main", File: "/tmp/dumpgen/main.go", Line: 45}},
+			},
+			{
+				ID:         6,
+				State:      "chan receive",
+				StackTrace: StackTrace{{Name: "main.(*Server).handle", File: "/tmp/dumpgen/main.go", Line: 14}},
+				Inlined:    []int{0},
+				CreatedBy:  FuncInfo{Name: "main.main", File: "/tmp/dumpgen/main.go", Line: 36},
+				CreatorID:  1,
+			},
+			{
+				ID:         7,
+				State:      "chan receive",
+				StackTrace: StackTrace{{Name: "main.(*Server).handle", File: "/tmp/dumpgen/main.go", Line: 14}},
+				Inlined:    []int{0},
+				CreatedBy:  FuncInfo{Name: "main.main", File: "/tmp/dumpgen/main.go", Line: 36},
+				CreatorID:  1,
+			},
+			{
+				ID:    8,
+				State: "sync.Mutex.Lock",
+				StackTrace: StackTrace{
+					{Name: "internal/sync.runtime_SemacquireMutex", File: "/usr/local/go/src/runtime/sema.go", Line: 95},
+					{Name: "internal/sync.(*Mutex).lockSlow", File: "/usr/local/go/src/internal/sync/mutex.go", Line: 149},
+					{Name: "internal/sync.(*Mutex).Lock", File: "/usr/local/go/src/internal/sync/mutex.go", Line: 70},
+					{Name: "sync.(*Mutex).Lock", File: "/usr/local/go/src/sync/mutex.go", Line: 46},
+					{Name: "main.main.func1", File: "/tmp/dumpgen/main.go", Line: 39},
+				},
+				Inlined:   []int{2, 3},
+				CreatedBy: FuncInfo{Name: "main.main", File: "/tmp/dumpgen/main.go", Line: 39},
+				CreatorID: 1,
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseGoroutineDump() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("GOTRACEBACK=system", func(t *testing.T) {
+		got, err := ParseGoroutineDump(readDump(t, "system.txt"))
+		if err != nil {
+			t.Fatalf("ParseGoroutineDump() error = %v", err)
+		}
+		if len(got) != 9 {
+			t.Fatalf("len() = %d, want 9", len(got))
+		}
+
+		want := FuncInfo{Name: "runtime.panicBounds64", File: "/usr/local/go/src/runtime/panic.go", Line: 236}
+		if got[0].ID != 9 || len(got[0].StackTrace) != 6 || got[0].StackTrace[1] != want {
+			t.Errorf("goroutine = %+v, want ID 9 with 6 frames and %v", got[0], want)
+		}
+		if got[1].State != "runnable" || got[2].State != "force gc (idle)" {
+			t.Errorf("State = %q, %q, want %q, %q", got[1].State, got[2].State, "runnable", "force gc (idle)")
+		}
+	})
+
+	t.Run("elided frames", func(t *testing.T) {
+		got, err := ParseGoroutineDump(readDump(t, "elided.txt"))
+		if err != nil {
+			t.Fatalf("ParseGoroutineDump() error = %v", err)
+		}
+
+		g := got[0]
+		if len(g.StackTrace) != 100 || g.ElidedFrames != 102 {
+			t.Errorf("len(StackTrace) = %d, ElidedFrames = %d, want 100, 102", len(g.StackTrace), g.ElidedFrames)
+		}
+		if last := g.StackTrace[len(g.StackTrace)-1]; last.Name != "main.main" {
+			t.Errorf("last frame = %v, want main.main", last)
+		}
+	})
+
+	t.Run("legacy", func(t *testing.T) {
+		got, err := ParseGoroutineDump(readDump(t, "legacy.txt"))
+		if err != nil {
+			t.Fatalf("ParseGoroutineDump() error = %v", err)
+		}
+
+		want := []Goroutine{
+			{
+				ID:             1,
+				State:          "running",
+				LockedToThread: true,
+				StackTrace: StackTrace{
+					{Name: "main.recurse", File: "/home/user/app/main.go", Line: 20},
+					{Name: "main.recurse", File: "/home/user/app/main.go", Line: 22},
+				},
+				ElidedFrames: -1,
+			},
+			{
+				ID:         17,
+				State:      "chan receive",
+				WaitTime:   12 * time.Minute,
+				StackTrace: StackTrace{{Name: "main.worker", File: "/home/user/app/worker.go", Line: 15}},
+				CreatedBy:  FuncInfo{Name: "main.main", File: "/home/user/app/main.go", Line: 11},
+			},
+			{
+				ID:         18,
+				State:      "select",
+				WaitTime:   time.Minute,
+				StackTrace: StackTrace{{Name: "main.(*Pool).run", File: "/home/user/app/pool.go", Line: 30}},
+				Inlined:    []int{0},
+				CreatedBy:  FuncInfo{Name: "main.(*Pool).Start", File: "/home/user/app/pool.go", Line: 21},
+			},
+			{
+				ID:        19,
+				State:     "running",
+				CreatedBy: FuncInfo{Name: "main.main", File: "/home/user/app/main.go", Line: 12},
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseGoroutineDump() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("current process", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+		go func() { <-block }()
+		runtime.Gosched()
+
+		buf := make([]byte, 1<<20)
+		got, err := ParseGoroutineDump(buf[:runtime.Stack(buf, true)])
+		if err != nil {
+			t.Fatalf("ParseGoroutineDump() error = %v", err)
+		}
+
+		const creator = "github.com/Siroshun09/serrors.TestParseGoroutineDump.func5"
+		for _, g := range got {
+			if g.CreatedBy.Name == creator && 0 < len(g.StackTrace) && g.StackTrace[0].Name == creator+".1" {
+				return
+			}
+		}
+		t.Errorf("goroutine created by %s is not found: %+v", creator, got)
+	})
+
+	tests := []struct {
+		name string
+		dump string
+	}{
+		{name: "empty", dump: ""},
+		{name: "no goroutines", dump: "main.main()\n\t/app/main.go:5\n"},
+		{name: "missing location", dump: "goroutine 1 [running]:\nmain.main()\n"},
+		{name: "invalid location", dump: "goroutine 1 [running]:\nmain.main()\n\t/app/main.go\n"},
+		{name: "invalid function", dump: "goroutine 1 [running]:\nmain.main\n\t/app/main.go:5\n"},
+		{name: "invalid creator", dump: "goroutine 1 [running]:\ncreated by main.main in goroutine x\n\t/app/main.go:5\n"},
+		{name: "invalid elided frames", dump: "goroutine 1 [running]:\n...x frames elided...\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := ParseGoroutineDump([]byte(tt.dump)); err == nil {
+				t.Errorf("ParseGoroutineDump() = %+v, want error", got)
+			}
+		})
+	}
+}
+
+func addDumpSeeds(f *testing.F) {
+	for _, name := range []string{"elided.txt", "legacy.txt", "panic.txt", "recovered.txt", "stack.txt", "system.txt"} {
+		f.Add(readDump(f, name))
+	}
+}
+
+func checkGoroutines(t *testing.T, goroutines []Goroutine) {
+	for _, g := range goroutines {
+		if g.ID <= 0 {
+			t.Errorf("ID = %d, want positive", g.ID)
+		}
+		for _, frame := range g.StackTrace {
+			if frame.Name == "" || frame.File == "" || frame.Line < 0 {
+				t.Errorf("invalid frame: %+v", frame)
+			}
+		}
+		for _, i := range g.Inlined {
+			if i < 0 || len(g.StackTrace) <= i {
+				t.Errorf("Inlined contains %d, but len(StackTrace) = %d", i, len(g.StackTrace))
+			}
+		}
+	}
+}
+
+func FuzzParseGoroutineDump(f *testing.F) {
+	addDumpSeeds(f)
+	f.Fuzz(func(t *testing.T, dump []byte) {
+		goroutines, err := ParseGoroutineDump(dump)
+		if err != nil {
+			return
+		}
+		if len(goroutines) == 0 {
+			t.Error("ParseGoroutineDump() returned no goroutines without error")
+		}
+		checkGoroutines(t, goroutines)
+	})
+}
+
+func FuzzParsePanic(f *testing.F) {
+	addDumpSeeds(f)
+	f.Fuzz(func(t *testing.T, output []byte) {
+		p, err := ParsePanic(output)
+		if err != nil {
+			return
+		}
+		if p.Message == "" {
+			t.Error("Message is empty")
+		}
+		checkGoroutines(t, p.Goroutines)
+	})
+}
@@ -0,0 +1,233 @@
+package serrors
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+)
+
+// Envelope is a portable representation of an error chain.
+//
+// An Envelope can be serialized with encoding/json to send errors across process boundaries.
+// Use Encode to create an Envelope and Decode to rebuild an error from it.
+type Envelope struct {
+	// Message is the message of the error.
+	Message string `json:"message"`
+	// Type is the concrete type name of the error, such as "*errors.errorString".
+	// It is only for display, and Decode does not depend on it.
+	Type string `json:"type"`
+	// Kind is set if the error attaches a StackTrace or attributes to its cause, and is empty for other errors.
+	// Decode uses it to restore these errors.
+	Kind EnvelopeKind `json:"kind,omitempty"`
+	// Sentinel is the name registered by RegisterSentinel if the error is a registered sentinel error.
+	Sentinel string `json:"sentinel,omitempty"`
+	// StackTrace is the StackTrace attached to the error.
+	// For errors returned from goroutines started by Go or Group.Go, this is the StackTrace of the goroutine that started them.
+	StackTrace StackTrace `json:"stacktrace,omitempty"`
+	// Omitted is the number of frames omitted from the end of StackTrace because of the max depth (see GetOmittedFrames).
+	Omitted int `json:"omitted,omitempty"`
+	// Attributes are the attributes attached to the error.
+	Attributes []EnvelopeAttr `json:"attributes,omitempty"`
+	// Causes are the envelopes of the wrapped errors.
+	Causes []*Envelope `json:"causes,omitempty"`
+}
+
+// EnvelopeKind identifies the errors that attach a StackTrace or attributes to their causes in an Envelope.
+//
+// The values are part of the serialized format, so they do not change between versions.
+type EnvelopeKind string
+
+const (
+	// EnvelopeKindStackTrace indicates an error that attaches Envelope.StackTrace to its cause.
+	EnvelopeKindStackTrace EnvelopeKind = "stacktrace"
+	// EnvelopeKindAttributes indicates an error that attaches Envelope.Attributes to its cause.
+	EnvelopeKindAttributes EnvelopeKind = "attributes"
+	// EnvelopeKindGoroutine indicates an error returned from a goroutine started by Go or Group.Go.
+	// Envelope.StackTrace is the StackTrace of the goroutine that started it.
+	EnvelopeKindGoroutine EnvelopeKind = "goroutine"
+)
+
+// EnvelopeAttr is a portable representation of an attribute attached by With.
+type EnvelopeAttr struct {
+	// Key is the key of the attribute.
+	Key string `json:"key"`
+	// Value is the value of the attribute.
+	Value any `json:"value"`
+}
+
+// RemoteError is an error rebuilt by Decode.
+//
+// RemoteError holds the message and the type name of the original error.
+type RemoteError struct {
+	// Message is the message of the original error.
+	Message string
+	// Type is the concrete type name of the original error.
+	Type   string
+	causes []error
+}
+
+func (e *RemoteError) Error() string {
+	return e.Message
+}
+
+func (e *RemoteError) Unwrap() []error {
+	return e.causes
+}
+
+// Encode converts err into an Envelope.
+//
+// Every layer of the error chain is encoded with its message and type name,
+// including the StackTrace and attributes attached by this package.
+//
+// If err is nil, this function returns nil.
+func Encode(err error) *Envelope {
+	if err == nil {
+		return nil
+	}
+
+	env := &Envelope{
+		Message:  err.Error(),
+		Type:     reflect.TypeOf(err).String(),
+		Sentinel: sentinelName(err),
+	}
+
+	switch x := err.(type) {
+	case *stackTraceError:
+		env.Kind = EnvelopeKindStackTrace
+		env.StackTrace, env.Omitted = x.getStackTrace(), x.omitted
+	case *attrError:
+		env.Kind = EnvelopeKindAttributes
+		env.Attributes = make([]EnvelopeAttr, len(x.attrs))
+		for i, attr := range x.attrs {
+			env.Attributes[i] = EnvelopeAttr{Key: attr.Key, Value: AttrValue(attr.Value)}
+		}
+	case *goroutineError:
+		env.Kind = EnvelopeKindGoroutine
+		env.StackTrace, env.Omitted = x.getCreatedBy(), x.omitted
+	case *RemoteError:
+		env.Type = x.Type
+	}
+
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := x.Unwrap(); cause != nil {
+			env.Causes = []*Envelope{Encode(cause)}
+		}
+	case interface{ Unwrap() []error }:
+		for _, cause := range x.Unwrap() {
+			if cause != nil {
+				env.Causes = append(env.Causes, Encode(cause))
+			}
+		}
+	}
+
+	return env
+}
+
+// Decode rebuilds an error from env.
+//
+// The returned error has the same messages as the original error chain.
+// StackTraces and attributes in env are attached again, so GetStackTraces and Attributes return the original ones.
+// Errors registered by RegisterSentinel are restored as-is, so errors.Is matches them.
+// Other errors are restored as *RemoteError.
+//
+// If env is nil, this function returns nil.
+func Decode(env *Envelope) error {
+	if env == nil {
+		return nil
+	}
+
+	if sentinel := lookupSentinel(env.Sentinel); sentinel != nil {
+		return sentinel
+	}
+
+	causes := make([]error, 0, len(env.Causes))
+	for _, cause := range env.Causes {
+		if err := Decode(cause); err != nil {
+			causes = append(causes, err)
+		}
+	}
+
+	if len(causes) == 1 {
+		switch env.Kind {
+		case EnvelopeKindStackTrace:
+			return &stackTraceError{err: causes[0], omitted: env.Omitted, stackTrace: env.StackTrace}
+		case EnvelopeKindAttributes:
+			attrs := make([]slog.Attr, len(env.Attributes))
+			for i, attr := range env.Attributes {
+				attrs[i] = slog.Any(attr.Key, attr.Value)
+			}
+			return &attrError{err: causes[0], attrs: attrs}
+		case EnvelopeKindGoroutine:
+			return &goroutineError{err: causes[0], omitted: env.Omitted, createdBy: env.StackTrace}
+		}
+	}
+
+	return &RemoteError{
+		Message: env.Message,
+		Type:    env.Type,
+		causes:  causes,
+	}
+}
+
+var sentinels = struct {
+	sync.RWMutex
+	byName map[string]error
+	// names holds the registered names in the order of registration.
+	names []string
+}{byName: map[string]error{}}
+
+// RegisterSentinel registers a sentinel error with the given name.
+//
+// Encode records the name of registered errors, and Decode restores them as-is,
+// so errors.Is works across process boundaries.
+// Both sides must register the same error with the same name.
+// If an error is registered with multiple names, Encode records the first one.
+//
+// This function panics if name is already registered with another error.
+func RegisterSentinel(name string, err error) {
+	if name == "" || err == nil {
+		panic("serrors: RegisterSentinel requires a non-empty name and a non-nil error")
+	}
+
+	sentinels.Lock()
+	defer sentinels.Unlock()
+
+	if registered, ok := sentinels.byName[name]; ok {
+		if !isSameError(registered, err) {
+			panic(fmt.Sprintf("serrors: sentinel %q is already registered", name))
+		}
+		return
+	}
+	sentinels.byName[name] = err
+	sentinels.names = append(sentinels.names, name)
+}
+
+func sentinelName(err error) string {
+	sentinels.RLock()
+	defer sentinels.RUnlock()
+
+	for _, name := range sentinels.names {
+		if isSameError(sentinels.byName[name], err) {
+			return name
+		}
+	}
+	return ""
+}
+
+func lookupSentinel(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	sentinels.RLock()
+	defer sentinels.RUnlock()
+
+	return sentinels.byName[name]
+}
+
+func isSameError(a, b error) bool {
+	// reflect.Value.Comparable also checks the values in interface fields, which make == panic if they are not comparable
+	return reflect.TypeOf(a) == reflect.TypeOf(b) && reflect.ValueOf(a).Comparable() && reflect.ValueOf(b).Comparable() && a == b
+}
@@ -0,0 +1,299 @@
+package serrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+var errEncodeTestSentinel = errors.New("sentinel")
+
+func init() {
+	RegisterSentinel("serrors.errEncodeTestSentinel", errEncodeTestSentinel)
+	RegisterSentinel("io.EOF", io.EOF)
+}
+
+func TestEncode(t *testing.T) {
+	stackTrace := StackTrace{{Name: "Test", File: "test.go", Line: 1}}
+
+	tests := []struct {
+		name string
+		err  error
+		want *Envelope
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "plain error",
+			err:  errors.New("test"),
+			want: &Envelope{Message: "test", Type: "*errors.errorString"},
+		},
+		{
+			name: "sentinel",
+			err:  fmt.Errorf("wrap: %w", io.EOF),
+			want: &Envelope{
+				Message: "wrap: EOF",
+				Type:    "*fmt.wrapError",
+				Causes:  []*Envelope{{Message: "EOF", Type: "*errors.errorString", Sentinel: "io.EOF"}},
+			},
+		},
+		{
+			name: "stack trace and attributes",
+			err: &attrError{
+				err:   &stackTraceError{err: errors.New("test"), stackTrace: stackTrace},
+				attrs: []slog.Attr{slog.String("key", "value"), slog.Group("group", slog.Int("n", 1))},
+			},
+			want: &Envelope{
+				Message:    "test",
+				Type:       "*serrors.attrError",
+				Kind:       EnvelopeKindAttributes,
+				Attributes: []EnvelopeAttr{{Key: "key", Value: "value"}, {Key: "group", Value: map[string]any{"n": int64(1)}}},
+				Causes: []*Envelope{{
+					Message:    "test",
+					Type:       "*serrors.stackTraceError",
+					Kind:       EnvelopeKindStackTrace,
+					StackTrace: stackTrace,
+					Causes:     []*Envelope{{Message: "test", Type: "*errors.errorString"}},
+				}},
+			},
+		},
+		{
+			name: "joined errors",
+			err:  &multipleErrorsWrapper{errs: []error{errors.New("test1"), nil, errors.New("test2")}},
+			want: &Envelope{
+				Message: "multiple errors",
+				Type:    "*serrors.multipleErrorsWrapper",
+				Causes: []*Envelope{
+					{Message: "test1", Type: "*errors.errorString"},
+					{Message: "test2", Type: "*errors.errorString"},
+				},
+			},
+		},
+		{
+			name: "remote error keeps the original type",
+			err:  &RemoteError{Message: "test", Type: "*remote.Error"},
+			want: &Envelope{Message: "test", Type: "*remote.Error"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Encode(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Encode() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	serr1 := New("test1")
+	serr2 := With(errors.New("test2"), "key", "value")
+	serr3 := WithStackTrace(errEncodeTestSentinel)
+
+	tests := []struct {
+		name            string
+		err             error
+		wantIs          []error
+		wantAttrs       []slog.Attr
+		wantStackTraces []StackTrace
+	}{
+		{
+			name: "plain error",
+			err:  errors.New("test"),
+		},
+		{
+			name:            "sentinel",
+			err:             fmt.Errorf("wrap: %w", serr3),
+			wantIs:          []error{errEncodeTestSentinel},
+			wantStackTraces: []StackTrace{GetStackTrace(serr3)},
+		},
+		{
+			name:            "joined errors with stack traces and attributes",
+			err:             fmt.Errorf("wrap: %w", errors.Join(serr1, io.EOF, serr2)),
+			wantIs:          []error{io.EOF},
+			wantAttrs:       []slog.Attr{slog.String("key", "value")},
+			wantStackTraces: []StackTrace{GetStackTrace(serr1), GetStackTrace(serr2)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(Encode(tt.err))
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+
+			var env Envelope
+			if err := json.Unmarshal(data, &env); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			got := Decode(&env)
+			if got.Error() != tt.err.Error() {
+				t.Errorf("Decode().Error() = %q, want %q", got.Error(), tt.err.Error())
+			}
+
+			for _, target := range tt.wantIs {
+				if !errors.Is(got, target) {
+					t.Errorf("errors.Is(Decode(), %v) = false, want true", target)
+				}
+			}
+
+			if attrs := Attributes(got); len(attrs) != len(tt.wantAttrs) {
+				t.Errorf("Attributes() = %v, want %v", attrs, tt.wantAttrs)
+			} else {
+				for i := range attrs {
+					if attrs[i].String() != tt.wantAttrs[i].String() {
+						t.Errorf("Attributes()[%d] = %v, want %v", i, attrs[i], tt.wantAttrs[i])
+					}
+				}
+			}
+
+			var stackTraces []StackTrace
+			for _, stackTrace := range GetStackTraces(got) {
+				stackTraces = append(stackTraces, stackTrace)
+			}
+			if !reflect.DeepEqual(stackTraces, tt.wantStackTraces) {
+				t.Errorf("GetStackTraces() = %v, want %v", stackTraces, tt.wantStackTraces)
+			}
+
+			var remoteErr *RemoteError
+			if !errors.As(got, &remoteErr) {
+				t.Fatalf("errors.As(Decode(), *RemoteError) = false, want true")
+			}
+			if want := reflect.TypeOf(tt.err).String(); remoteErr.Type != want {
+				t.Errorf("RemoteError.Type = %v, want %v", remoteErr.Type, want)
+			}
+		})
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		if got := Decode(nil); got != nil {
+			t.Errorf("Decode() = %v, want nil", got)
+		}
+	})
+
+	t.Run("omitted frames", func(t *testing.T) {
+		err := &goroutineError{
+			err:       &stackTraceError{err: errors.New("test"), omitted: 3, stackTrace: StackTrace{{Name: "Test1", File: "test.go", Line: 1}}},
+			omitted:   -1,
+			createdBy: StackTrace{{Name: "Test2", File: "test.go", Line: 2}},
+		}
+
+		got := Tree(Decode(Encode(err)))
+		if got.Omitted != 3 || got.CreatedByOmitted != -1 {
+			t.Errorf("Omitted = %d, CreatedByOmitted = %d, want 3 and -1", got.Omitted, got.CreatedByOmitted)
+		}
+	})
+
+	t.Run("kind", func(t *testing.T) {
+		stackTrace := StackTrace{{Name: "Test", File: "test.go", Line: 1}}
+		got := Decode(&Envelope{
+			Message:    "test",
+			Type:       "*serrors.renamedError",
+			Kind:       EnvelopeKindStackTrace,
+			StackTrace: stackTrace,
+			Causes:     []*Envelope{{Message: "test", Type: "*errors.errorString"}},
+		})
+		if attached, ok := GetAttachedStackTrace(got); !ok || !reflect.DeepEqual(attached, stackTrace) {
+			t.Errorf("GetAttachedStackTrace() = (%v, %v), want %v", attached, ok, stackTrace)
+		}
+	})
+
+	t.Run("type name without kind", func(t *testing.T) {
+		got := Decode(&Envelope{
+			Message:    "test",
+			Type:       "*serrors.stackTraceError",
+			StackTrace: StackTrace{{Name: "Test", File: "test.go", Line: 1}},
+			Causes:     []*Envelope{{Message: "test", Type: "*errors.errorString"}},
+		})
+		if _, ok := GetAttachedStackTrace(got); ok {
+			t.Errorf("Decode() = %#v, want an error without a StackTrace", got)
+		}
+	})
+
+	t.Run("unregistered sentinel", func(t *testing.T) {
+		got := Decode(&Envelope{Message: "test", Type: "*errors.errorString", Sentinel: "unknown"})
+		if !reflect.DeepEqual(got, &RemoteError{Message: "test", Type: "*errors.errorString", causes: []error{}}) {
+			t.Errorf("Decode() = %#v, want *RemoteError", got)
+		}
+	})
+}
+
+type encodeTestValueError struct {
+	value any
+}
+
+func (e encodeTestValueError) Error() string {
+	return fmt.Sprint(e.value)
+}
+
+func TestEncode_sentinel(t *testing.T) {
+	RegisterSentinel("serrors.encodeTestValueError", encodeTestValueError{value: 1})
+	RegisterSentinel("serrors.encodeTestMapError", encodeTestValueError{value: map[string]int{}})
+	RegisterSentinel("serrors.errEncodeTestSentinel2", errEncodeTestSentinel)
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "comparable value", err: encodeTestValueError{value: 1}, want: "serrors.encodeTestValueError"},
+		{name: "uncomparable value", err: encodeTestValueError{value: map[string]int{}}, want: ""},
+		{name: "registered twice", err: errEncodeTestSentinel, want: "serrors.errEncodeTestSentinel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Encode(tt.err).Sentinel; got != tt.want {
+				t.Errorf("Encode().Sentinel = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterSentinel(t *testing.T) {
+	t.Run("register same error twice", func(t *testing.T) {
+		RegisterSentinel("io.EOF", io.EOF)
+	})
+
+	tests := []struct {
+		name    string
+		key     string
+		err     error
+		wantMsg string
+	}{
+		{
+			name:    "empty name",
+			key:     "",
+			err:     io.EOF,
+			wantMsg: "serrors: RegisterSentinel requires a non-empty name and a non-nil error",
+		},
+		{
+			name:    "nil error",
+			key:     "nil",
+			err:     nil,
+			wantMsg: "serrors: RegisterSentinel requires a non-empty name and a non-nil error",
+		},
+		{
+			name:    "duplicate name",
+			key:     "io.EOF",
+			err:     io.ErrUnexpectedEOF,
+			wantMsg: `serrors: sentinel "io.EOF" is already registered`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tt.wantMsg {
+					t.Errorf("RegisterSentinel() panic = %v, want %v", r, tt.wantMsg)
+				}
+			}()
+			RegisterSentinel(tt.key, tt.err)
+		})
+	}
+}
@@ -5,6 +5,9 @@ go 1.24
 require (
 	github.com/Siroshun09/logs v1.3.0
 	github.com/Siroshun09/logs/logmock v1.0.0
-	github.com/Siroshun09/serrors v1.4.0
+	github.com/Siroshun09/serrors v1.5.0
 	go.uber.org/mock v0.6.0
 )
+
+// serrors v1.5.0 is not tagged yet, so it is built from the parent directory until then.
+replace github.com/Siroshun09/serrors v1.5.0 => ../
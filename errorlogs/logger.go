@@ -3,6 +3,7 @@ package errorlogs
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/Siroshun09/logs"
 	"github.com/Siroshun09/serrors"
@@ -24,11 +25,30 @@ func NewLoggerWithOption(out logs.Logger, opt LoggerOption) logs.Logger {
 // LoggerOption is the option for logger implementation.
 type LoggerOption struct {
 	// StackTraceLogLevel is the log level for stack trace.
+	//
+	// Attributes attached by serrors.With are also printed at this level.
 	StackTraceLogLevel StackTraceLogLevel
 	// PrintStackTraceOnWarn is whether to print stack trace on Warn.
 	PrintStackTraceOnWarn bool
 	// PrintCurrentStackTraceIfNotAttached is whether to print the current stack trace if the error does not have a stack trace.
 	PrintCurrentStackTraceIfNotAttached bool
+	// PrintAllStackTraces is whether to print every stack trace in the error chain using serrors.GetAllStackTraces.
+	//
+	// If false, only the stack traces returned by serrors.GetStackTraceEntries are printed.
+	// Truncated stack traces end with "... N more frames" in both cases.
+	PrintAllStackTraces bool
+	// FrameFilters are the filters applied to stack traces before printing them.
+	//
+	// If nil, serrors.DefaultFrameFilters is used.
+	FrameFilters []serrors.FrameFilter
+	// PrintFingerprint is whether to print the fingerprint of the error (see serrors.Fingerprint) on Warn and Error.
+	//
+	// The fingerprint is printed at StackTraceLogLevel, and can be used as the group ID of the same failures.
+	PrintFingerprint bool
+	// FingerprintFilters are the filters passed to serrors.Fingerprint.
+	//
+	// If nil, the runtime and standard library frames are ignored.
+	FingerprintFilters []serrors.FrameFilter
 }
 
 // StackTraceLogLevel is the log level for stack trace.
@@ -72,6 +92,8 @@ func (l *logger) Warn(ctx context.Context, err error) {
 	}
 
 	l.dedicated.Warn(ctx, err)
+	l.printFingerprint(ctx, err)
+	l.printAttributes(ctx, err)
 	if l.opt.PrintStackTraceOnWarn {
 		l.printStackTraces(ctx, err)
 	}
@@ -94,6 +116,8 @@ func (l *logger) Error(ctx context.Context, err error) {
 	}
 
 	l.dedicated.Error(ctx, err)
+	l.printFingerprint(ctx, err)
+	l.printAttributes(ctx, err)
 	l.printStackTraces(ctx, err)
 }
 
@@ -106,38 +130,94 @@ func (l *logger) Errorf(ctx context.Context, format string, args ...any) {
 	l.printStackTraces(ctx, nil)
 }
 
-const stackTraceLogFormat = "stacktrace\n%s"
+const (
+	stackTraceLogFormat  = "stacktrace\n%s"
+	attributesLogFormat  = "attributes\n%s"
+	fingerprintLogFormat = "fingerprint: %s"
+)
+
+func (l *logger) printFingerprint(ctx context.Context, err error) {
+	if l == nil || !l.opt.PrintFingerprint || err == nil {
+		return
+	}
+
+	l.printDetail(ctx, fingerprintLogFormat, serrors.Fingerprint(err, l.opt.FingerprintFilters...))
+}
+
+func (l *logger) printAttributes(ctx context.Context, err error) {
+	if l == nil {
+		return
+	}
+
+	attrs := serrors.Attributes(err)
+	if len(attrs) == 0 {
+		return
+	}
+
+	lines := make([]string, 0, len(attrs))
+	for _, attr := range attrs {
+		lines = append(lines, attr.String())
+	}
+	l.printDetail(ctx, attributesLogFormat, strings.Join(lines, "\n"))
+}
 
 func (l *logger) printStackTraces(ctx context.Context, err error) {
 	if l == nil {
 		return
 	}
 
+	entries := serrors.GetStackTraceEntries(err)
+	if l.opt.PrintAllStackTraces {
+		entries = serrors.GetAllStackTraces(err)
+	}
+
 	found := false
-	for _, stackTrace := range serrors.GetStackTraces(err) {
-		l.printStackTrace(ctx, stackTrace)
+	for entry := range entries {
+		l.printStackTrace(ctx, entry.StackTrace, entry.Omitted)
 		found = true
 	}
 
 	if !found && l.opt.PrintCurrentStackTraceIfNotAttached {
-		l.printStackTrace(ctx, serrors.GetCurrentStackTrace())
+		l.printStackTrace(ctx, serrors.GetCurrentStackTrace(), 0)
 		return
 	}
 }
 
-func (l *logger) printStackTrace(ctx context.Context, stackTrace serrors.StackTrace) {
+// printStackTrace prints stackTrace, followed by "... N more frames" if omitted is not 0.
+func (l *logger) printStackTrace(ctx context.Context, stackTrace serrors.StackTrace, omitted int) {
 	if l == nil {
 		return
 	}
 
+	switch filters := l.opt.FrameFilters; {
+	case filters == nil:
+		stackTrace = stackTrace.FilterDefault()
+	case 0 < len(filters):
+		stackTrace = stackTrace.Filter(filters...)
+	}
+
+	if omitted == 0 {
+		l.printDetail(ctx, stackTraceLogFormat, stackTrace)
+		return
+	}
+
+	text := serrors.FormatOmittedFrames(omitted)
+	if 0 < len(stackTrace) {
+		text = stackTrace.String() + "\n" + text
+	}
+	l.printDetail(ctx, stackTraceLogFormat, text)
+}
+
+// printDetail prints additional information of errors at StackTraceLogLevel.
+func (l *logger) printDetail(ctx context.Context, format string, arg any) {
 	switch l.opt.StackTraceLogLevel {
 	case StackTraceLogLevelDebug:
-		l.dedicated.Debug(ctx, fmt.Sprintf(stackTraceLogFormat, stackTrace))
+		l.dedicated.Debug(ctx, fmt.Sprintf(format, arg))
 	case StackTraceLogLevelInfo:
-		l.dedicated.Info(ctx, fmt.Sprintf(stackTraceLogFormat, stackTrace))
+		l.dedicated.Info(ctx, fmt.Sprintf(format, arg))
 	case StackTraceLogLevelWarn:
-		l.dedicated.Warnf(ctx, stackTraceLogFormat, stackTrace)
+		l.dedicated.Warnf(ctx, format, arg)
 	case StackTraceLogLevelError:
-		l.dedicated.Errorf(ctx, stackTraceLogFormat, stackTrace)
+		l.dedicated.Errorf(ctx, format, arg)
 	}
 }
@@ -20,8 +20,16 @@ func CallPrintStackTraces(ctx context.Context, err error, target logs.Logger) {
 	castLogger(target).printStackTraces(ctx, err)
 }
 
+func CallPrintAttributes(ctx context.Context, err error, target logs.Logger) {
+	castLogger(target).printAttributes(ctx, err)
+}
+
+func CallPrintFingerprint(ctx context.Context, err error, target logs.Logger) {
+	castLogger(target).printFingerprint(ctx, err)
+}
+
 func CallPrintStackTrace(ctx context.Context, target logs.Logger) {
-	castLogger(target).printStackTrace(ctx, serrors.GetCurrentStackTrace())
+	castLogger(target).printStackTrace(ctx, serrors.GetCurrentStackTrace(), 0)
 }
 
 // GetStackTraceLogFormat exposes the internal stackTraceLogFormat for external tests.
@@ -29,6 +37,16 @@ func GetStackTraceLogFormat() string {
 	return stackTraceLogFormat
 }
 
+// GetAttributesLogFormat exposes the internal attributesLogFormat for external tests.
+func GetAttributesLogFormat() string {
+	return attributesLogFormat
+}
+
+// GetFingerprintLogFormat exposes the internal fingerprintLogFormat for external tests.
+func GetFingerprintLogFormat() string {
+	return fingerprintLogFormat
+}
+
 func NewNilLogger() logs.Logger {
 	return (*logger)(nil)
 }
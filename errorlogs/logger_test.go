@@ -12,6 +12,7 @@ import (
 	"github.com/Siroshun09/logs/logmock"
 	"github.com/Siroshun09/serrors"
 	"github.com/Siroshun09/serrors/errorlogs"
+	"github.com/Siroshun09/serrors/serrorstest"
 	"go.uber.org/mock/gomock"
 )
 
@@ -170,6 +171,28 @@ func TestLogger_Warn(t *testing.T) {
 				mock.EXPECT().Warn(ctx, err)
 			},
 		},
+		{
+			name: "attributes attached / PrintStackTraceOnWarn = false",
+			opt: errorlogs.LoggerOption{
+				PrintStackTraceOnWarn: false,
+			},
+			err: serrors.With(errors.New("test"), "key", "value"),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				mock.EXPECT().Warn(ctx, err)
+				mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetAttributesLogFormat(), "key=value"))
+			},
+		},
+		{
+			name: "PrintFingerprint = true / PrintStackTraceOnWarn = false",
+			opt: errorlogs.LoggerOption{
+				PrintFingerprint: true,
+			},
+			err: serrors.New("test"),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				mock.EXPECT().Warn(ctx, err)
+				mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetFingerprintLogFormat(), serrors.Fingerprint(err)))
+			},
+		},
 		{
 			name: "stacktrace not attached / PrintStackTraceOnWarn = true / PrintCurrentStackTraceIfNotAttached = true",
 			opt: errorlogs.LoggerOption{
@@ -343,6 +366,31 @@ func TestLogger_Error(t *testing.T) {
 				mock.EXPECT().Error(ctx, err)
 			},
 		},
+		{
+			name: "attributes attached",
+			opt:  errorlogs.LoggerOption{},
+			err:  serrors.With(errors.New("test"), "key", "value"),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				gomock.InOrder(
+					mock.EXPECT().Error(ctx, err),
+					mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetAttributesLogFormat(), "key=value")),
+					mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetStackTraceLogFormat(), serrors.GetStackTrace(err))),
+				)
+			},
+		},
+		{
+			name: "PrintFingerprint = true",
+			opt:  errorlogs.LoggerOption{PrintFingerprint: true},
+			err:  serrors.With(errors.New("test"), "key", "value"),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				gomock.InOrder(
+					mock.EXPECT().Error(ctx, err),
+					mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetFingerprintLogFormat(), serrors.Fingerprint(err))),
+					mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetAttributesLogFormat(), "key=value")),
+					mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetStackTraceLogFormat(), serrors.GetStackTrace(err))),
+				)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -468,6 +516,50 @@ func TestLogger_printStackTrace(t *testing.T) {
 				mock.EXPECT().Debug(ctx, gomock.AssignableToTypeOf(stringType)) // print current stacktrace
 			},
 		},
+		{
+			name: "nested stacktraces / PrintAllStackTraces = false",
+			opt:  errorlogs.LoggerOption{},
+			err:  newNestedStackTraceError(),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetStackTraceLogFormat(), nestedOuterStackTrace))
+			},
+		},
+		{
+			name: "nested stacktraces / PrintAllStackTraces = true",
+			opt:  errorlogs.LoggerOption{PrintAllStackTraces: true},
+			err:  newNestedStackTraceError(),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				gomock.InOrder(
+					mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetStackTraceLogFormat(), nestedOuterStackTrace)),
+					mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetStackTraceLogFormat(), nestedInnerStackTrace)),
+				)
+			},
+		},
+		{
+			name: "truncated stacktrace",
+			opt:  errorlogs.LoggerOption{FrameFilters: []serrors.FrameFilter{}},
+			err: serrors.Decode(&serrors.Envelope{
+				Message:    "test",
+				Kind:       serrors.EnvelopeKindStackTrace,
+				StackTrace: nestedInnerStackTrace,
+				Omitted:    3,
+				Causes:     []*serrors.Envelope{{Message: "test"}},
+			}),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetStackTraceLogFormat(), nestedInnerStackTrace.String()+"\n... 3 more frames"))
+			},
+		},
+		{
+			name: "stacktrace attached error / FrameFilters",
+			opt: errorlogs.LoggerOption{
+				FrameFilters: []serrors.FrameFilter{serrors.WithoutRuntime(), serrors.WithoutStdlib()},
+			},
+			err: serrors.New("test"),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				filtered := serrors.GetStackTrace(err).Filter(serrors.WithoutRuntime(), serrors.WithoutStdlib())
+				mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetStackTraceLogFormat(), filtered))
+			},
+		},
 		{
 			name: "stacktrace attached error / log level: info",
 			opt: errorlogs.LoggerOption{
@@ -512,6 +604,142 @@ func TestLogger_printStackTrace(t *testing.T) {
 	}
 }
 
+var (
+	nestedOuterStackTrace = serrors.StackTrace{{Name: "outer", File: "outer.go", Line: 1}}
+	nestedInnerStackTrace = serrors.StackTrace{{Name: "inner", File: "inner.go", Line: 2}}
+)
+
+// newNestedStackTraceError creates an error that has a stack trace wrapping another error that has a stack trace,
+// like errors received from other processes.
+func newNestedStackTraceError() error {
+	inner := serrorstest.WithStackTrace(errors.New("test"), nestedInnerStackTrace)
+	return serrorstest.WithStackTrace(fmt.Errorf("wrap: %w", inner), nestedOuterStackTrace)
+}
+
+func TestLogger_printAttributes(t *testing.T) {
+	tests := []struct {
+		name   string
+		opt    errorlogs.LoggerOption
+		err    error
+		expect func(ctx context.Context, err error, mock *logmock.MockLogger)
+	}{
+		{
+			name: "nil",
+			opt:  errorlogs.LoggerOption{},
+			err:  nil,
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				// expect nothing to be called
+			},
+		},
+		{
+			name: "attributes not attached",
+			opt:  errorlogs.LoggerOption{},
+			err:  serrors.New("test"),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				// expect nothing to be called
+			},
+		},
+		{
+			name: "multiple attributes",
+			opt:  errorlogs.LoggerOption{},
+			err:  serrors.With(fmt.Errorf("wrap: %w", serrors.With(errors.New("test"), "inner", 1)), "outer", 2),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetAttributesLogFormat(), "outer=2\ninner=1"))
+			},
+		},
+		{
+			name: "log level: error",
+			opt: errorlogs.LoggerOption{
+				StackTraceLogLevel: errorlogs.StackTraceLogLevelError,
+			},
+			err: serrors.With(errors.New("test"), "key", "value"),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				mock.EXPECT().Errorf(ctx, errorlogs.GetAttributesLogFormat(), "key=value")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			mockLogger := logmock.NewMockLogger(gomock.NewController(t))
+
+			tt.expect(ctx, tt.err, mockLogger)
+
+			l := errorlogs.NewLoggerWithOption(mockLogger, tt.opt)
+			errorlogs.CallPrintAttributes(ctx, tt.err, l)
+		})
+	}
+}
+
+func TestLogger_printFingerprint(t *testing.T) {
+	keepAll := func(serrors.FuncInfo) bool { return true }
+
+	tests := []struct {
+		name   string
+		opt    errorlogs.LoggerOption
+		err    error
+		expect func(ctx context.Context, err error, mock *logmock.MockLogger)
+	}{
+		{
+			name: "nil",
+			opt:  errorlogs.LoggerOption{PrintFingerprint: true},
+			err:  nil,
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				// expect nothing to be called
+			},
+		},
+		{
+			name: "PrintFingerprint = false",
+			opt:  errorlogs.LoggerOption{},
+			err:  serrors.New("test"),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				// expect nothing to be called
+			},
+		},
+		{
+			name: "stacktrace not attached",
+			opt:  errorlogs.LoggerOption{PrintFingerprint: true},
+			err:  errors.New("test"),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetFingerprintLogFormat(), serrors.Fingerprint(err)))
+			},
+		},
+		{
+			name: "FingerprintFilters",
+			opt: errorlogs.LoggerOption{
+				PrintFingerprint:   true,
+				FingerprintFilters: []serrors.FrameFilter{keepAll},
+			},
+			err: serrors.New("test"),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				mock.EXPECT().Debug(ctx, fmt.Sprintf(errorlogs.GetFingerprintLogFormat(), serrors.Fingerprint(err, keepAll)))
+			},
+		},
+		{
+			name: "log level: warn",
+			opt: errorlogs.LoggerOption{
+				PrintFingerprint:   true,
+				StackTraceLogLevel: errorlogs.StackTraceLogLevelWarn,
+			},
+			err: serrors.New("test"),
+			expect: func(ctx context.Context, err error, mock *logmock.MockLogger) {
+				mock.EXPECT().Warnf(ctx, errorlogs.GetFingerprintLogFormat(), serrors.Fingerprint(err))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			mockLogger := logmock.NewMockLogger(gomock.NewController(t))
+
+			tt.expect(ctx, tt.err, mockLogger)
+
+			l := errorlogs.NewLoggerWithOption(mockLogger, tt.opt)
+			errorlogs.CallPrintFingerprint(ctx, tt.err, l)
+		})
+	}
+}
+
 func TestLogger_Nil(t *testing.T) {
 	l := errorlogs.NewNilLogger()
 
@@ -524,5 +752,7 @@ func TestLogger_Nil(t *testing.T) {
 	l.Error(ctx, errors.New("test"))
 	l.Errorf(ctx, "test %s", "arg")
 	errorlogs.CallPrintStackTraces(ctx, errors.New("test"), l)
+	errorlogs.CallPrintAttributes(ctx, serrors.With(errors.New("test"), "key", "value"), l)
+	errorlogs.CallPrintFingerprint(ctx, serrors.New("test"), l)
 	errorlogs.CallPrintStackTrace(ctx, l)
 }
@@ -0,0 +1,109 @@
+package serrors
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// FrameFilter reports whether the FuncInfo should be kept in a StackTrace.
+type FrameFilter func(FuncInfo) bool
+
+// Filter returns a new StackTrace that contains only the FuncInfo accepted by all filters.
+//
+// The original StackTrace is not modified.
+func (s StackTrace) Filter(filters ...FrameFilter) StackTrace {
+	filtered := make(StackTrace, 0, len(s))
+	for _, funcInfo := range s {
+		if acceptFrame(funcInfo, filters) {
+			filtered = append(filtered, funcInfo)
+		}
+	}
+	return filtered
+}
+
+func acceptFrame(funcInfo FuncInfo, filters []FrameFilter) bool {
+	for _, filter := range filters {
+		if !filter(funcInfo) {
+			return false
+		}
+	}
+	return true
+}
+
+// WithoutRuntime returns a FrameFilter that drops the frames of the runtime package, such as runtime.goexit.
+func WithoutRuntime() FrameFilter {
+	return DropPrefix("runtime")
+}
+
+// WithoutStdlib returns a FrameFilter that drops the frames of the standard library, such as testing.tRunner and net/http.
+//
+// A package is considered to be in the standard library if the first element of its import path does not contain a dot,
+// except for the main package. Packages vendored by the standard library are also dropped.
+func WithoutStdlib() FrameFilter {
+	return func(funcInfo FuncInfo) bool {
+		pkg, _ := splitFuncName(funcInfo.Name)
+		if pkg == "" || pkg == "main" {
+			return true
+		}
+		if strings.HasPrefix(pkg, "vendor/") {
+			// packages vendored by the standard library
+			return false
+		}
+		first, _, _ := strings.Cut(pkg, "/")
+		return strings.Contains(first, ".")
+	}
+}
+
+// OnlyModule returns a FrameFilter that keeps only the frames of the packages in the module of the given path.
+func OnlyModule(path string) FrameFilter {
+	return func(funcInfo FuncInfo) bool {
+		return hasPackagePrefix(funcPackage(funcInfo.Name), path)
+	}
+}
+
+// DropPrefix returns a FrameFilter that drops the frames of the given packages and their subpackages.
+func DropPrefix(pkgs ...string) FrameFilter {
+	return func(funcInfo FuncInfo) bool {
+		pkg := funcPackage(funcInfo.Name)
+		for _, prefix := range pkgs {
+			if hasPackagePrefix(pkg, prefix) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func hasPackagePrefix(pkg, prefix string) bool {
+	return pkg == prefix || strings.HasPrefix(pkg, strings.TrimSuffix(prefix, "/")+"/")
+}
+
+var defaultFrameFilters atomic.Pointer[[]FrameFilter]
+
+// SetDefaultFrameFilters sets the FrameFilter applied when StackTraces are printed, such as by %+v.
+//
+// The StackTraces attached to errors are not modified, so the raw StackTraces are still returned by GetStackTraces.
+// Calling this function without filters removes the default filters.
+func SetDefaultFrameFilters(filters ...FrameFilter) {
+	defaultFrameFilters.Store(&filters)
+}
+
+// FilterDefault returns the StackTrace filtered by the FrameFilter set by SetDefaultFrameFilters.
+//
+// If no default filters are set, the StackTrace is returned as-is.
+func (s StackTrace) FilterDefault() StackTrace {
+	filters := DefaultFrameFilters()
+	if len(filters) == 0 {
+		return s
+	}
+	return s.Filter(filters...)
+}
+
+// DefaultFrameFilters returns the FrameFilter set by SetDefaultFrameFilters.
+func DefaultFrameFilters() []FrameFilter {
+	filters := defaultFrameFilters.Load()
+	if filters == nil {
+		return nil
+	}
+	return *filters
+}
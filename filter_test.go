@@ -0,0 +1,164 @@
+package serrors
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestStackTrace_Filter(t *testing.T) {
+	stackTrace := StackTrace{
+		{Name: "github.com/x/y/pkg.(*Server).handle.func2"},
+		{Name: "github.com/x/y/internal/middleware.Recover"},
+		{Name: "github.com/x/y2/pkg.Func"},
+		{Name: "gopkg.in/yaml%2ev3.Unmarshal"},
+		{Name: "net/http.HandlerFunc.ServeHTTP"},
+		{Name: "vendor/golang.org/x/net/http2/hpack.(*Decoder).Write"},
+		{Name: "main.main"},
+		{Name: "testing.tRunner"},
+		{Name: "runtime/debug.Stack"},
+		{Name: "runtime.goexit"},
+	}
+
+	tests := []struct {
+		name    string
+		filters []FrameFilter
+		want    []string
+	}{
+		{
+			name:    "no filters",
+			filters: nil,
+			want: []string{
+				"github.com/x/y/pkg.(*Server).handle.func2",
+				"github.com/x/y/internal/middleware.Recover",
+				"github.com/x/y2/pkg.Func",
+				"gopkg.in/yaml%2ev3.Unmarshal",
+				"net/http.HandlerFunc.ServeHTTP",
+				"vendor/golang.org/x/net/http2/hpack.(*Decoder).Write",
+				"main.main",
+				"testing.tRunner",
+				"runtime/debug.Stack",
+				"runtime.goexit",
+			},
+		},
+		{
+			name:    "WithoutRuntime",
+			filters: []FrameFilter{WithoutRuntime()},
+			want: []string{
+				"github.com/x/y/pkg.(*Server).handle.func2",
+				"github.com/x/y/internal/middleware.Recover",
+				"github.com/x/y2/pkg.Func",
+				"gopkg.in/yaml%2ev3.Unmarshal",
+				"net/http.HandlerFunc.ServeHTTP",
+				"vendor/golang.org/x/net/http2/hpack.(*Decoder).Write",
+				"main.main",
+				"testing.tRunner",
+			},
+		},
+		{
+			name:    "WithoutStdlib",
+			filters: []FrameFilter{WithoutStdlib()},
+			want: []string{
+				"github.com/x/y/pkg.(*Server).handle.func2",
+				"github.com/x/y/internal/middleware.Recover",
+				"github.com/x/y2/pkg.Func",
+				"gopkg.in/yaml%2ev3.Unmarshal",
+				"main.main",
+			},
+		},
+		{
+			name:    "OnlyModule",
+			filters: []FrameFilter{OnlyModule("github.com/x/y")},
+			want: []string{
+				"github.com/x/y/pkg.(*Server).handle.func2",
+				"github.com/x/y/internal/middleware.Recover",
+			},
+		},
+		{
+			name:    "OnlyModule with escaped package path",
+			filters: []FrameFilter{OnlyModule("gopkg.in/yaml.v3")},
+			want: []string{
+				"gopkg.in/yaml%2ev3.Unmarshal",
+			},
+		},
+		{
+			name:    "OnlyModule and DropPrefix",
+			filters: []FrameFilter{OnlyModule("github.com/x/y"), DropPrefix("github.com/x/y/internal/")},
+			want: []string{
+				"github.com/x/y/pkg.(*Server).handle.func2",
+			},
+		},
+		{
+			name:    "DropPrefix with multiple packages",
+			filters: []FrameFilter{DropPrefix("github.com/x", "net/http", "testing", "runtime")},
+			want: []string{
+				"gopkg.in/yaml%2ev3.Unmarshal",
+				"vendor/golang.org/x/net/http2/hpack.(*Decoder).Write",
+				"main.main",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, funcInfo := range stackTrace.Filter(tt.filters...) {
+				got = append(got, funcInfo.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("original is not modified", func(t *testing.T) {
+		original := StackTrace{{Name: "runtime.goexit"}, {Name: "main.main"}}
+		_ = original.Filter(WithoutRuntime())
+		if original[0].Name != "runtime.goexit" || len(original) != 2 {
+			t.Errorf("original = %v, want not modified", original)
+		}
+	})
+}
+
+func TestSetDefaultFrameFilters(t *testing.T) {
+	t.Cleanup(func() { SetDefaultFrameFilters() })
+
+	if got := DefaultFrameFilters(); len(got) != 0 {
+		t.Fatalf("DefaultFrameFilters() = %v, want empty", got)
+	}
+
+	err := &stackTraceError{
+		err:        errors.New("test"),
+		stackTrace: StackTrace{{Name: "main.main", File: "main.go", Line: 1}, {Name: "runtime.goexit", File: "asm.s", Line: 2}},
+	}
+
+	SetDefaultFrameFilters(WithoutRuntime())
+	if got := len(DefaultFrameFilters()); got != 1 {
+		t.Errorf("len(DefaultFrameFilters()) = %v, want 1", got)
+	}
+	if got, want := fmt.Sprintf("%+v", err), "test\nstacktrace: test\n\tmain.main (main.go:1)"; got != want {
+		t.Errorf("Sprintf(%%+v) = %q, want %q", got, want)
+	}
+	if stackTrace, _ := GetAttachedStackTrace(err); len(stackTrace) != 2 {
+		t.Errorf("GetAttachedStackTrace() = %v, want the raw stack trace", stackTrace)
+	}
+
+	SetDefaultFrameFilters()
+	if got, want := fmt.Sprintf("%+v", err), "test\nstacktrace: test\n\tmain.main (main.go:1)\n\truntime.goexit (asm.s:2)"; got != want {
+		t.Errorf("Sprintf(%%+v) = %q, want %q", got, want)
+	}
+}
+
+func TestStackTrace_FilterDefault(t *testing.T) {
+	t.Cleanup(func() { SetDefaultFrameFilters() })
+
+	stackTrace := StackTrace{{Name: "main.main", File: "main.go", Line: 1}, {Name: "runtime.goexit", File: "asm.s", Line: 2}}
+	if got := stackTrace.FilterDefault(); !reflect.DeepEqual(got, stackTrace) {
+		t.Errorf("FilterDefault() = %v, want %v", got, stackTrace)
+	}
+
+	SetDefaultFrameFilters(WithoutRuntime())
+	if got, want := stackTrace.FilterDefault(), stackTrace[:1]; !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterDefault() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+package serrors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+)
+
+// Fingerprint returns a stable hash of the function names in the StackTrace.
+//
+// Line numbers and file paths are not included, so the same call path produces the same fingerprint
+// even if the binary is built from a different revision or in a different directory.
+// Only the frames accepted by all filters are hashed. If no filters are given, WithoutRuntime and WithoutStdlib are used.
+func (s StackTrace) Fingerprint(filters ...FrameFilter) string {
+	h := sha256.New()
+	writeFingerprintFrames(h, s, fingerprintFilters(filters))
+	return fingerprintSum(h)
+}
+
+// Fingerprint returns a stable hash of the error tree of err to group the same failures.
+//
+// The hash is computed from the concrete type names in the error tree (see Tree) and the function names of their StackTraces,
+// so the messages, line numbers and file paths do not change the fingerprint.
+// The filters are applied to the StackTraces in the same way as StackTrace.Fingerprint.
+//
+// If err is nil, this function returns an empty string.
+func Fingerprint(err error, filters ...FrameFilter) string {
+	node := Tree(err)
+	if node == nil {
+		return ""
+	}
+
+	h := sha256.New()
+	writeFingerprintNode(h, node, fingerprintFilters(filters))
+	return fingerprintSum(h)
+}
+
+func fingerprintFilters(filters []FrameFilter) []FrameFilter {
+	if len(filters) == 0 {
+		return []FrameFilter{WithoutRuntime(), WithoutStdlib()}
+	}
+	return filters
+}
+
+func writeFingerprintNode(h hash.Hash, node *Node, filters []FrameFilter) {
+	_, _ = io.WriteString(h, "type "+node.Type+"\n")
+	writeFingerprintFrames(h, node.StackTrace, filters)
+	if node.CreatedBy != nil {
+		_, _ = io.WriteString(h, "created by\n")
+		writeFingerprintFrames(h, node.CreatedBy, filters)
+	}
+
+	// the markers keep the shape of the tree, so that a sibling is not confused with a child
+	_, _ = io.WriteString(h, "(\n")
+	for _, child := range node.Children {
+		writeFingerprintNode(h, child, filters)
+	}
+	_, _ = io.WriteString(h, ")\n")
+}
+
+func writeFingerprintFrames(h hash.Hash, stackTrace StackTrace, filters []FrameFilter) {
+	for _, funcInfo := range stackTrace {
+		if acceptFrame(funcInfo, filters) {
+			_, _ = io.WriteString(h, "func "+funcInfo.Name+"\n")
+		}
+	}
+}
+
+func fingerprintSum(h hash.Hash) string {
+	// 128 bits are enough to identify groups, and keep the fingerprint short in logs
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
@@ -0,0 +1,172 @@
+package serrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestStackTrace_Fingerprint(t *testing.T) {
+	base := StackTrace{
+		{Name: "example.com/app.handle", File: "/src/app/handler.go", Line: 10},
+		{Name: "example.com/app.main", File: "/src/app/main.go", Line: 20},
+	}
+	keepAll := func(FuncInfo) bool { return true }
+
+	tests := []struct {
+		name      string
+		other     StackTrace
+		filters   []FrameFilter
+		wantEqual bool
+	}{
+		{
+			name:      "same stack trace",
+			other:     base,
+			wantEqual: true,
+		},
+		{
+			name: "different lines and files",
+			other: StackTrace{
+				{Name: "example.com/app.handle", File: "/build/app/handler.go", Line: 12},
+				{Name: "example.com/app.main", File: "/build/app/main.go", Line: 25},
+			},
+			wantEqual: true,
+		},
+		{
+			name: "runtime and stdlib frames",
+			other: StackTrace{
+				{Name: "example.com/app.handle", File: "/src/app/handler.go", Line: 10},
+				{Name: "net/http.HandlerFunc.ServeHTTP", File: "/usr/local/go/src/net/http/server.go", Line: 2220},
+				{Name: "example.com/app.main", File: "/src/app/main.go", Line: 20},
+				{Name: "runtime.main", File: "/usr/local/go/src/runtime/proc.go", Line: 283},
+				{Name: "runtime.goexit", File: "/usr/local/go/src/runtime/asm_amd64.s", Line: 1700},
+			},
+			wantEqual: true,
+		},
+		{
+			name: "runtime frames / custom filters",
+			other: StackTrace{
+				{Name: "example.com/app.handle", File: "/src/app/handler.go", Line: 10},
+				{Name: "example.com/app.main", File: "/src/app/main.go", Line: 20},
+				{Name: "runtime.goexit", File: "/usr/local/go/src/runtime/asm_amd64.s", Line: 1700},
+			},
+			filters:   []FrameFilter{keepAll},
+			wantEqual: false,
+		},
+		{
+			name: "different function",
+			other: StackTrace{
+				{Name: "example.com/app.serve", File: "/src/app/handler.go", Line: 10},
+				{Name: "example.com/app.main", File: "/src/app/main.go", Line: 20},
+			},
+			wantEqual: false,
+		},
+		{
+			name: "different order",
+			other: StackTrace{
+				{Name: "example.com/app.main", File: "/src/app/main.go", Line: 20},
+				{Name: "example.com/app.handle", File: "/src/app/handler.go", Line: 10},
+			},
+			wantEqual: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, got := base.Fingerprint(tt.filters...), tt.other.Fingerprint(tt.filters...)
+			if (got == want) != tt.wantEqual {
+				t.Errorf("Fingerprint() = %s, base = %s, wantEqual %v", got, want, tt.wantEqual)
+			}
+			if len(got) != 32 {
+				t.Errorf("len(Fingerprint()) = %d, want 32", len(got))
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	stackTrace1 := StackTrace{{Name: "example.com/app.handle", File: "handler.go", Line: 10}}
+	stackTrace2 := StackTrace{{Name: "example.com/app.serve", File: "handler.go", Line: 20}}
+	newErr := func(msg string, stackTrace StackTrace) error {
+		return &stackTraceError{err: errors.New(msg), stackTrace: stackTrace}
+	}
+	base := fmt.Errorf("wrap: %w", newErr("test1", stackTrace1))
+
+	tests := []struct {
+		name      string
+		other     error
+		wantEqual bool
+	}{
+		{
+			name:      "different messages",
+			other:     fmt.Errorf("other: %w", newErr("test2", stackTrace1)),
+			wantEqual: true,
+		},
+		{
+			name:      "different lines",
+			other:     fmt.Errorf("wrap: %w", newErr("test1", StackTrace{{Name: "example.com/app.handle", File: "handler.go", Line: 11}})),
+			wantEqual: true,
+		},
+		{
+			name:      "attributes",
+			other:     fmt.Errorf("wrap: %w", &attrError{err: newErr("test1", stackTrace1)}),
+			wantEqual: true,
+		},
+		{
+			name:      "different stack trace",
+			other:     fmt.Errorf("wrap: %w", newErr("test1", stackTrace2)),
+			wantEqual: false,
+		},
+		{
+			name:      "different type",
+			other:     fmt.Errorf("wrap: %w", &stackTraceError{err: &RemoteError{Message: "test1"}, stackTrace: stackTrace1}),
+			wantEqual: false,
+		},
+		{
+			name:      "not wrapped",
+			other:     newErr("wrap: test1", stackTrace1),
+			wantEqual: false,
+		},
+		{
+			name:      "created by",
+			other:     fmt.Errorf("wrap: %w", &goroutineError{err: newErr("test1", stackTrace1), createdBy: stackTrace2}),
+			wantEqual: false,
+		},
+		{
+			name:      "joined errors",
+			other:     fmt.Errorf("wrap: %w", errors.Join(newErr("test1", stackTrace1))),
+			wantEqual: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, got := Fingerprint(base), Fingerprint(tt.other)
+			if (got == want) != tt.wantEqual {
+				t.Errorf("Fingerprint() = %s, base = %s, wantEqual %v", got, want, tt.wantEqual)
+			}
+		})
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		if got := Fingerprint(nil); got != "" {
+			t.Errorf("Fingerprint() = %q, want empty", got)
+		}
+	})
+
+	t.Run("same call site", func(t *testing.T) {
+		var fingerprints []string
+		for i := range 2 {
+			fingerprints = append(fingerprints, Fingerprint(Errorf("test %d", i)))
+		}
+		if fingerprints[0] != fingerprints[1] {
+			t.Errorf("Fingerprint() = %v, want the same fingerprints", fingerprints)
+		}
+	})
+
+	t.Run("sibling and child", func(t *testing.T) {
+		sibling := &multipleErrorsWrapper{errs: []error{&multipleErrorsWrapper{}, errors.New("a")}}
+		child := &multipleErrorsWrapper{errs: []error{&multipleErrorsWrapper{errs: []error{errors.New("a")}}}}
+		if Fingerprint(sibling) == Fingerprint(child) {
+			t.Errorf("Fingerprint() of different trees are the same: %s", Fingerprint(sibling))
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package serrors
+
+import (
+	"fmt"
+	"io"
+)
+
+// Format implements fmt.Formatter.
+//
+// The verb %+v prints the error message followed by the attributes returned by Attributes and every StackTrace found by GetStackTraces.
+// The StackTraces are filtered by DefaultFrameFilters, and truncated StackTraces end with "... N more frames".
+// The verb %#v prints a Go-syntax representation of the error.
+// Other verbs are applied to the error message, so %v, %s and %q behave as they do for plain errors.
+func (e *stackTraceError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('#') {
+		_, _ = fmt.Fprintf(s, "&serrors.stackTraceError{err:%#v, stackTrace:%#v}", e.err, e.getStackTrace())
+		return
+	}
+	formatError(s, verb, e)
+}
+
+func formatError(s fmt.State, verb rune, err error) {
+	if verb == 'v' && s.Flag('+') {
+		_, _ = io.WriteString(s, err.Error())
+		writeAttributes(s, err)
+		writeStackTraces(s, err)
+		return
+	}
+	_, _ = fmt.Fprintf(s, fmt.FormatString(s, verb), err.Error())
+}
+
+func writeAttributes(w io.Writer, err error) {
+	attrs := Attributes(err)
+	if len(attrs) == 0 {
+		return
+	}
+
+	_, _ = io.WriteString(w, "\nattributes:")
+	for _, attr := range attrs {
+		_, _ = io.WriteString(w, " "+attr.String())
+	}
+}
+
+func writeStackTraces(w io.Writer, err error) {
+	tryYieldStackTrace(err, nil, func(entries []StackTraceEntry) bool {
+		_, _ = io.WriteString(w, "\nstacktrace: "+entries[0].Err.Error())
+		for _, entry := range entries {
+			for _, funcInfo := range entry.StackTrace.FilterDefault() {
+				_, _ = io.WriteString(w, "\n\t"+funcInfo.String())
+			}
+			if entry.Omitted != 0 {
+				_, _ = io.WriteString(w, "\n\t"+FormatOmittedFrames(entry.Omitted))
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,129 @@
+package serrors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStackTraceError_Format(t *testing.T) {
+	stackTrace1 := StackTrace{{Name: "Test1", File: "test.go", Line: 1}, {Name: "Test2", File: "test.go", Line: 2}}
+	stackTrace2 := StackTrace{{Name: "Test3", File: "test.go", Line: 3}}
+	serr1 := &stackTraceError{err: errors.New("test1"), stackTrace: stackTrace1}
+	serr2 := &stackTraceError{err: errors.New("test2"), stackTrace: stackTrace2}
+
+	tests := []struct {
+		name   string
+		format string
+		err    error
+		want   string
+	}{
+		{
+			name:   "%v",
+			format: "%v",
+			err:    serr1,
+			want:   "test1",
+		},
+		{
+			name:   "%s",
+			format: "%s",
+			err:    serr1,
+			want:   "test1",
+		},
+		{
+			name:   "%q",
+			format: "%q",
+			err:    serr1,
+			want:   `"test1"`,
+		},
+		{
+			name:   "%10s",
+			format: "%10s",
+			err:    serr1,
+			want:   "     test1",
+		},
+		{
+			name:   "%x",
+			format: "%x",
+			err:    serr1,
+			want:   "7465737431",
+		},
+		{
+			name:   "%+v",
+			format: "%+v",
+			err:    serr1,
+			want:   "test1\nstacktrace: test1\n\tTest1 (test.go:1)\n\tTest2 (test.go:2)",
+		},
+		{
+			name:   "%+v with joined errors",
+			format: "%+v",
+			err:    &stackTraceError{err: errors.Join(serr1, serr2), stackTrace: StackTrace{}},
+			want:   "test1\ntest2\nstacktrace: test1\ntest2",
+		},
+		{
+			name:   "%+v with wrapped joined errors",
+			format: "%+v",
+			err:    &stackTraceError{err: fmt.Errorf("wrap: %w", errors.Join(serr1, errors.New("plain"), serr2)), stackTrace: StackTrace{}},
+			want:   "wrap: test1\nplain\ntest2\nstacktrace: wrap: test1\nplain\ntest2",
+		},
+		{
+			name:   "%#v",
+			format: "%#v",
+			err:    serr2,
+			want:   `&serrors.stackTraceError{err:&errors.errorString{s:"test2"}, stackTrace:serrors.StackTrace{serrors.FuncInfo{Name:"Test3", File:"test.go", Line:3}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, tt.err); got != tt.want {
+				t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writeStackTraces(t *testing.T) {
+	serr1 := &stackTraceError{err: errors.New("test1"), stackTrace: StackTrace{{Name: "Test1", File: "test.go", Line: 1}}}
+	serr2 := &stackTraceError{err: errors.New("test2"), stackTrace: StackTrace{{Name: "Test2", File: "test.go", Line: 2}}}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: "",
+		},
+		{
+			name: "no stack trace",
+			err:  errors.New("test"),
+			want: "",
+		},
+		{
+			name: "joined errors",
+			err:  fmt.Errorf("wrap: %w", errors.Join(serr1, errors.New("plain"), serr2)),
+			want: "\nstacktrace: test1\n\tTest1 (test.go:1)\nstacktrace: test2\n\tTest2 (test.go:2)",
+		},
+		{
+			name: "omitted frames",
+			err: &goroutineError{
+				err:       &stackTraceError{err: errors.New("test"), omitted: 2, stackTrace: StackTrace{{Name: "Test1", File: "test.go", Line: 1}}},
+				omitted:   -1,
+				createdBy: StackTrace{{Name: "Test2", File: "test.go", Line: 2}},
+			},
+			want: "\nstacktrace: test\n\tTest1 (test.go:1)\n\t... 2 more frames\n\tTest2 (test.go:2)\n\t... more frames",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			writeStackTraces(&b, tt.err)
+			if got := b.String(); got != tt.want {
+				t.Errorf("writeStackTraces() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package serrors
+
+import (
+	"strings"
+)
+
+// Package returns the import path of the package in which the function is defined.
+//
+// For vendored packages, the path is the import path without the vendor directory.
+// If the package cannot be determined, this method returns an empty string.
+func (s FuncInfo) Package() string {
+	return funcPackage(s.Name)
+}
+
+// Receiver returns the receiver type of the method, such as "*Server" or "Server".
+//
+// Type parameters are omitted. If the function is not a method, this method returns an empty string.
+func (s FuncInfo) Receiver() string {
+	receiver, _, _ := s.parse()
+	return receiver
+}
+
+// Method returns the name of the function or method without its package, receiver and closure suffixes.
+//
+// Type parameters are omitted. For example, "handle" is returned for "pkg.(*Server).handle.func2".
+func (s FuncInfo) Method() string {
+	_, method, _ := s.parse()
+	return method
+}
+
+// IsClosure reports whether the function is a closure (function literal) or a compiler-generated wrapper of it.
+func (s FuncInfo) IsClosure() bool {
+	_, _, closure := s.parse()
+	return closure
+}
+
+// ShortName returns the function name qualified by the last element of its package path, such as "pkg.(*Server).handle.func2".
+func (s FuncInfo) ShortName() string {
+	pkg, rest := splitFuncName(s.Name)
+	if pkg == "" {
+		return rest
+	}
+	return pkg[strings.LastIndexByte(pkg, '/')+1:] + "." + rest
+}
+
+func (s FuncInfo) parse() (receiver, method string, closure bool) {
+	_, rest := splitFuncName(s.Name)
+	rest = stripTypeParams(rest)
+
+	if strings.HasPrefix(rest, "(") {
+		end := strings.IndexByte(rest, ')')
+		if end < 0 {
+			return "", rest, false
+		}
+		receiver = rest[1:end]
+		method, suffix, _ := strings.Cut(strings.TrimPrefix(rest[end+1:], "."), ".")
+		return receiver, strings.TrimSuffix(method, "-fm"), suffix != ""
+	}
+
+	segments := strings.Split(rest, ".")
+	method, segments = segments[0], segments[1:]
+	if 0 < len(segments) && segments[0] != "" && !isClosureSegment(segments[0]) {
+		// value receiver, such as "Server.handle"
+		receiver, method, segments = method, segments[0], segments[1:]
+	}
+	if method == "init" && 0 < len(segments) && isDigits(segments[0]) {
+		// numbered init functions, such as "init.0"
+		method, segments = method+"."+segments[0], segments[1:]
+	}
+	return receiver, strings.TrimSuffix(method, "-fm"), 0 < len(segments)
+}
+
+// isClosureSegment reports whether the segment is a name of a closure or a compiler-generated wrapper, such as "func1", "gowrap1" or "2".
+func isClosureSegment(segment string) bool {
+	for _, prefix := range []string{"func", "gowrap", "deferwrap"} {
+		if n, ok := strings.CutPrefix(segment, prefix); ok && isDigits(n) {
+			return true
+		}
+	}
+	return isDigits(segment)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || '9' < r {
+			return false
+		}
+	}
+	return true
+}
+
+// stripTypeParams removes type parameters, such as "[...]" in "Map[...]".
+func stripTypeParams(s string) string {
+	if !strings.Contains(s, "[") {
+		return s
+	}
+
+	var b strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']' && 0 < depth:
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// funcPackage returns the import path of the package in the function name reported by runtime.Frame.Function.
+func funcPackage(name string) string {
+	pkg, _ := splitFuncName(name)
+	if i := strings.LastIndex(pkg, "/vendor/"); 0 <= i {
+		return pkg[i+len("/vendor/"):]
+	}
+	return strings.TrimPrefix(pkg, "vendor/")
+}
+
+// splitFuncName splits the function name reported by runtime.Frame.Function into the package path and the rest.
+//
+// The dots in the last element of the package path are escaped as %2e by the compiler,
+// so the first dot after the last slash separates the package path from the function name.
+func splitFuncName(name string) (pkg, rest string) {
+	// type parameters may contain slashes, such as "pkg.F[go.shape.*example.com/x.T]"
+	head := name
+	if i := strings.IndexByte(head, '['); 0 <= i {
+		head = head[:i]
+	}
+
+	lastSlash := strings.LastIndexByte(head, '/')
+	dot := strings.IndexByte(head[lastSlash+1:], '.')
+	if dot < 0 {
+		return "", name
+	}
+
+	dot += lastSlash + 1
+	return strings.ReplaceAll(name[:dot], "%2e", "."), name[dot+1:]
+}
@@ -0,0 +1,275 @@
+package serrors
+
+import "testing"
+
+func Test_splitFuncName(t *testing.T) {
+	tests := []struct {
+		name     string
+		funcName string
+		wantPkg  string
+		wantRest string
+	}{
+		{
+			name:     "function",
+			funcName: "github.com/x/y/pkg.Func",
+			wantPkg:  "github.com/x/y/pkg",
+			wantRest: "Func",
+		},
+		{
+			name:     "method with closure",
+			funcName: "github.com/x/y/pkg.(*Server).handle.func2",
+			wantPkg:  "github.com/x/y/pkg",
+			wantRest: "(*Server).handle.func2",
+		},
+		{
+			name:     "standard library",
+			funcName: "runtime.goexit",
+			wantPkg:  "runtime",
+			wantRest: "goexit",
+		},
+		{
+			name:     "escaped dot in package path",
+			funcName: "gopkg.in/yaml%2ev3.Unmarshal",
+			wantPkg:  "gopkg.in/yaml.v3",
+			wantRest: "Unmarshal",
+		},
+		{
+			name:     "type parameters with slashes",
+			funcName: "example.com/pkg.Map[go.shape.*example.com/x.T]",
+			wantPkg:  "example.com/pkg",
+			wantRest: "Map[go.shape.*example.com/x.T]",
+		},
+		{
+			name:     "vendored package",
+			funcName: "github.com/x/y/vendor/github.com/a/b.Func",
+			wantPkg:  "github.com/x/y/vendor/github.com/a/b",
+			wantRest: "Func",
+		},
+		{
+			name:     "no package",
+			funcName: "unknown",
+			wantPkg:  "",
+			wantRest: "unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, rest := splitFuncName(tt.funcName)
+			if pkg != tt.wantPkg || rest != tt.wantRest {
+				t.Errorf("splitFuncName() = (%v, %v), want (%v, %v)", pkg, rest, tt.wantPkg, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestFuncInfo_parse(t *testing.T) {
+	tests := []struct {
+		name          string
+		funcName      string
+		wantPackage   string
+		wantReceiver  string
+		wantMethod    string
+		wantClosure   bool
+		wantShortName string
+	}{
+		{
+			name:          "function",
+			funcName:      "github.com/x/y/pkg.Func",
+			wantPackage:   "github.com/x/y/pkg",
+			wantMethod:    "Func",
+			wantShortName: "pkg.Func",
+		},
+		{
+			name:          "pointer receiver",
+			funcName:      "github.com/x/y/pkg.(*Server).handle",
+			wantPackage:   "github.com/x/y/pkg",
+			wantReceiver:  "*Server",
+			wantMethod:    "handle",
+			wantShortName: "pkg.(*Server).handle",
+		},
+		{
+			name:          "pointer receiver with closure",
+			funcName:      "github.com/x/y/pkg.(*Server).handle.func2",
+			wantPackage:   "github.com/x/y/pkg",
+			wantReceiver:  "*Server",
+			wantMethod:    "handle",
+			wantClosure:   true,
+			wantShortName: "pkg.(*Server).handle.func2",
+		},
+		{
+			name:          "value receiver",
+			funcName:      "github.com/x/y/pkg.Server.handle",
+			wantPackage:   "github.com/x/y/pkg",
+			wantReceiver:  "Server",
+			wantMethod:    "handle",
+			wantShortName: "pkg.Server.handle",
+		},
+		{
+			name:          "value receiver with nested closure",
+			funcName:      "github.com/x/y/pkg.Server.handle.func1.2",
+			wantPackage:   "github.com/x/y/pkg",
+			wantReceiver:  "Server",
+			wantMethod:    "handle",
+			wantClosure:   true,
+			wantShortName: "pkg.Server.handle.func1.2",
+		},
+		{
+			name:          "closure",
+			funcName:      "main.main.func1",
+			wantPackage:   "main",
+			wantMethod:    "main",
+			wantClosure:   true,
+			wantShortName: "main.main.func1",
+		},
+		{
+			name:          "go statement wrapper",
+			funcName:      "main.main.gowrap1",
+			wantPackage:   "main",
+			wantMethod:    "main",
+			wantClosure:   true,
+			wantShortName: "main.main.gowrap1",
+		},
+		{
+			name:          "method value",
+			funcName:      "net/http.(*Server).Serve-fm",
+			wantPackage:   "net/http",
+			wantReceiver:  "*Server",
+			wantMethod:    "Serve",
+			wantShortName: "http.(*Server).Serve-fm",
+		},
+		{
+			name:          "generic function",
+			funcName:      "github.com/x/y/pkg.Map[...]",
+			wantPackage:   "github.com/x/y/pkg",
+			wantMethod:    "Map",
+			wantShortName: "pkg.Map[...]",
+		},
+		{
+			name:          "generic function with closure",
+			funcName:      "github.com/x/y/pkg.Map[go.shape.*example.com/x.T].func1",
+			wantPackage:   "github.com/x/y/pkg",
+			wantMethod:    "Map",
+			wantClosure:   true,
+			wantShortName: "pkg.Map[go.shape.*example.com/x.T].func1",
+		},
+		{
+			name:          "generic receiver",
+			funcName:      "github.com/x/y/pkg.(*List[...]).Push",
+			wantPackage:   "github.com/x/y/pkg",
+			wantReceiver:  "*List",
+			wantMethod:    "Push",
+			wantShortName: "pkg.(*List[...]).Push",
+		},
+		{
+			name:          "generic value receiver",
+			funcName:      "github.com/x/y/pkg.Set[...].Has",
+			wantPackage:   "github.com/x/y/pkg",
+			wantReceiver:  "Set",
+			wantMethod:    "Has",
+			wantShortName: "pkg.Set[...].Has",
+		},
+		{
+			name:          "package-level closure",
+			funcName:      "github.com/x/y/pkg.glob..func1",
+			wantPackage:   "github.com/x/y/pkg",
+			wantMethod:    "glob",
+			wantClosure:   true,
+			wantShortName: "pkg.glob..func1",
+		},
+		{
+			name:          "numbered init function",
+			funcName:      "github.com/x/y/pkg.init.0",
+			wantPackage:   "github.com/x/y/pkg",
+			wantMethod:    "init.0",
+			wantShortName: "pkg.init.0",
+		},
+		{
+			name:          "closure in numbered init function",
+			funcName:      "github.com/x/y/pkg.init.0.func1",
+			wantPackage:   "github.com/x/y/pkg",
+			wantMethod:    "init.0",
+			wantClosure:   true,
+			wantShortName: "pkg.init.0.func1",
+		},
+		{
+			name:          "escaped package path",
+			funcName:      "gopkg.in/yaml%2ev3.(*decoder).unmarshal",
+			wantPackage:   "gopkg.in/yaml.v3",
+			wantReceiver:  "*decoder",
+			wantMethod:    "unmarshal",
+			wantShortName: "yaml.v3.(*decoder).unmarshal",
+		},
+		{
+			name:          "vendored package",
+			funcName:      "github.com/x/y/vendor/github.com/a/b.Func",
+			wantPackage:   "github.com/a/b",
+			wantMethod:    "Func",
+			wantShortName: "b.Func",
+		},
+		{
+			name:          "package vendored by the standard library",
+			funcName:      "vendor/golang.org/x/net/http2/hpack.(*Decoder).Write",
+			wantPackage:   "golang.org/x/net/http2/hpack",
+			wantReceiver:  "*Decoder",
+			wantMethod:    "Write",
+			wantShortName: "hpack.(*Decoder).Write",
+		},
+		{
+			name:          "runtime",
+			funcName:      "runtime.goexit",
+			wantPackage:   "runtime",
+			wantMethod:    "goexit",
+			wantShortName: "runtime.goexit",
+		},
+		{
+			name:          "empty",
+			funcName:      "",
+			wantShortName: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			funcInfo := FuncInfo{Name: tt.funcName}
+			if got := funcInfo.Package(); got != tt.wantPackage {
+				t.Errorf("Package() = %v, want %v", got, tt.wantPackage)
+			}
+			if got := funcInfo.Receiver(); got != tt.wantReceiver {
+				t.Errorf("Receiver() = %v, want %v", got, tt.wantReceiver)
+			}
+			if got := funcInfo.Method(); got != tt.wantMethod {
+				t.Errorf("Method() = %v, want %v", got, tt.wantMethod)
+			}
+			if got := funcInfo.IsClosure(); got != tt.wantClosure {
+				t.Errorf("IsClosure() = %v, want %v", got, tt.wantClosure)
+			}
+			if got := funcInfo.ShortName(); got != tt.wantShortName {
+				t.Errorf("ShortName() = %v, want %v", got, tt.wantShortName)
+			}
+		})
+	}
+}
+
+type funcNameTestServer struct{}
+
+//go:noinline
+func (*funcNameTestServer) handle() FuncInfo {
+	return func() FuncInfo {
+		return GetCurrentStackTrace()[0]
+	}()
+}
+
+func TestFuncInfo_parse_captured(t *testing.T) {
+	funcInfo := (&funcNameTestServer{}).handle()
+	if got := funcInfo.Package(); got != "github.com/Siroshun09/serrors" {
+		t.Errorf("Package() = %v, want github.com/Siroshun09/serrors", got)
+	}
+	if got := funcInfo.Receiver(); got != "*funcNameTestServer" {
+		t.Errorf("Receiver() = %v, want *funcNameTestServer", got)
+	}
+	if got := funcInfo.Method(); got != "handle" {
+		t.Errorf("Method() = %v, want handle", got)
+	}
+	if !funcInfo.IsClosure() {
+		t.Errorf("IsClosure() = false, want true")
+	}
+}
@@ -0,0 +1,132 @@
+package serrors
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// goroutineError is an error returned from a goroutine started by Go or Group.Go.
+//
+// It holds the StackTrace of the goroutine that started the goroutine, like "created by" in Go's tracebacks.
+type goroutineError struct {
+	err error
+	// pcs holds the program counters captured when the goroutine was started.
+	pcs []uintptr
+	// omitted is the number of frames that were not captured because of the max depth.
+	omitted   int
+	resolve   sync.Once
+	createdBy StackTrace
+}
+
+func (e *goroutineError) Error() string {
+	return e.err.Error()
+}
+
+func (e *goroutineError) Unwrap() error {
+	return e.err
+}
+
+// Format implements fmt.Formatter in the same way as the errors that have a StackTrace.
+func (e *goroutineError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('#') {
+		_, _ = fmt.Fprintf(s, "&serrors.goroutineError{err:%#v, createdBy:%#v}", e.err, e.getCreatedBy())
+		return
+	}
+	formatError(s, verb, e)
+}
+
+func (e *goroutineError) getCreatedBy() StackTrace {
+	e.resolve.Do(func() {
+		if e.pcs != nil {
+			e.createdBy = newStackTraceFromPCs(e.pcs)
+			e.pcs = nil
+		}
+	})
+	return e.createdBy
+}
+
+func withCreatedBy(err error, pcs []uintptr, omitted int) error {
+	if err == nil {
+		return nil
+	}
+
+	return &goroutineError{
+		err:     err,
+		pcs:     pcs,
+		omitted: omitted,
+	}
+}
+
+// Go calls fn in a new goroutine and returns a channel that receives its result.
+//
+// If fn returns an error or panics, the error has the StackTrace of the caller of Go as its "created by" StackTrace.
+// GetStackTraces appends it to the StackTraces of the error.
+// A panic is converted into an error in the same way as Recover.
+//
+// The channel receives exactly one value (nil if fn succeeds) and is closed after that.
+func Go(fn func() error) <-chan error {
+	pcs, omitted := callers(1, MaxStackDepth()) // Go
+	ch := make(chan error, 1)
+
+	go func() {
+		defer close(ch)
+		ch <- withCreatedBy(Catch(fn), pcs, omitted)
+	}()
+
+	return ch
+}
+
+// Group is a collection of goroutines working on subtasks of the same overall task, like errgroup.Group.
+//
+// Errors returned from the goroutines have the "created by" StackTrace in the same way as Go.
+//
+// A zero Group is valid and does not cancel on error.
+type Group struct {
+	cancel  func(error)
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+// WithContext returns a new Group and an associated Context derived from ctx.
+//
+// The derived Context is canceled the first time a function passed to Go returns an error or panics,
+// or the first time Wait returns, whichever occurs first.
+// The cause of the cancellation is the first error.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go calls fn in a new goroutine.
+//
+// The first call to return a non-nil error cancels the Group's context, if the Group was created by WithContext.
+// The error will be returned by Wait.
+// A panic is converted into an error in the same way as Recover.
+func (g *Group) Go(fn func() error) {
+	pcs, omitted := callers(1, MaxStackDepth()) // Group.Go
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		if err := withCreatedBy(Catch(fn), pcs, omitted); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel(err)
+				}
+			})
+		}
+	}()
+}
+
+// Wait blocks until all function calls from the Go method have returned, then returns the first non-nil error (if any) from them.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	return g.err
+}
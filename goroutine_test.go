@@ -0,0 +1,177 @@
+package serrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+//go:noinline
+func spawnGo(fn func() error) <-chan error {
+	return Go(fn)
+}
+
+func TestGo(t *testing.T) {
+	tests := []struct {
+		name            string
+		fn              func() error
+		wantNil         bool
+		wantOwnTrace    bool
+		wantPanic       bool
+		wantErrorString string
+	}{
+		{
+			name:    "success",
+			fn:      func() error { return nil },
+			wantNil: true,
+		},
+		{
+			name:            "error without stack trace",
+			fn:              func() error { return errors.New("test") },
+			wantErrorString: "test",
+		},
+		{
+			name:            "error with stack trace",
+			fn:              func() error { return New("test") },
+			wantOwnTrace:    true,
+			wantErrorString: "test",
+		},
+		{
+			name:            "panic",
+			fn:              func() error { panicWithValue("test"); return nil },
+			wantOwnTrace:    true,
+			wantPanic:       true,
+			wantErrorString: "panic: test",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := spawnGo(tt.fn)
+			err, ok := <-ch
+			if !ok {
+				t.Fatalf("channel is closed before receiving the result")
+			}
+			if _, ok := <-ch; ok {
+				t.Errorf("channel is not closed after receiving the result")
+			}
+
+			if tt.wantNil {
+				if err != nil {
+					t.Errorf("Go() = %v, want nil", err)
+				}
+				return
+			}
+
+			if err.Error() != tt.wantErrorString {
+				t.Errorf("Go().Error() = %v, want %v", err.Error(), tt.wantErrorString)
+			}
+
+			var panicErr *PanicError
+			if errors.As(err, &panicErr) != tt.wantPanic {
+				t.Errorf("errors.As(Go(), *PanicError) = %v, want %v", !tt.wantPanic, tt.wantPanic)
+			}
+
+			var stackTraces []StackTrace
+			for _, stackTrace := range GetStackTraces(err) {
+				stackTraces = append(stackTraces, stackTrace)
+			}
+			if len(stackTraces) != 1 {
+				t.Fatalf("GetStackTraces() = %v, want 1 stack trace", stackTraces)
+			}
+
+			stackTrace := stackTraces[0]
+			createdBy := slices.IndexFunc(stackTrace, func(funcInfo FuncInfo) bool {
+				return funcInfo.Name == "github.com/Siroshun09/serrors.spawnGo"
+			})
+			switch {
+			case createdBy < 0:
+				t.Errorf("stack trace = %v, want to contain spawnGo", stackTrace)
+			case tt.wantOwnTrace && createdBy == 0:
+				t.Errorf("stack trace = %v, want to start with the goroutine's own frames", stackTrace)
+			case !tt.wantOwnTrace && createdBy != 0:
+				t.Errorf("stack trace = %v, want to start with spawnGo", stackTrace)
+			}
+
+			if formatted := fmt.Sprintf("%+v", err); !strings.Contains(formatted, "github.com/Siroshun09/serrors.spawnGo") {
+				t.Errorf("Sprintf(%%+v) = %v, want to contain spawnGo", formatted)
+			}
+		})
+	}
+}
+
+func TestGroup(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		g, ctx := WithContext(t.Context())
+		for range 3 {
+			g.Go(func() error { return nil })
+		}
+		if err := g.Wait(); err != nil {
+			t.Errorf("Wait() = %v, want nil", err)
+		}
+		if ctx.Err() == nil {
+			t.Errorf("ctx.Err() = nil, want canceled after Wait")
+		}
+	})
+
+	t.Run("first error cancels context", func(t *testing.T) {
+		g, ctx := WithContext(t.Context())
+		want := errors.New("test")
+
+		g.Go(func() error { return want })
+		g.Go(func() error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		err := g.Wait()
+		if !errors.Is(err, want) {
+			t.Errorf("Wait() = %v, want %v", err, want)
+		}
+		if cause := context.Cause(ctx); !errors.Is(cause, want) {
+			t.Errorf("context.Cause() = %v, want %v", cause, want)
+		}
+
+		stackTrace, ok := firstStackTrace(err)
+		if !ok || stackTrace[0].Name != "github.com/Siroshun09/serrors.TestGroup.func2" {
+			t.Errorf("stack trace = %v, want to start with the caller of Group.Go", stackTrace)
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		var g Group
+		g.Go(func() error { panicWithValue("test"); return nil })
+
+		var panicErr *PanicError
+		if err := g.Wait(); !errors.As(err, &panicErr) {
+			t.Errorf("Wait() = %v, want *PanicError", err)
+		}
+	})
+}
+
+func Test_goroutineError_Encode(t *testing.T) {
+	err := <-spawnGo(func() error { return New("test") })
+
+	got := Decode(Encode(err))
+
+	var want []StackTrace
+	for _, stackTrace := range GetStackTraces(err) {
+		want = append(want, stackTrace)
+	}
+	var stackTraces []StackTrace
+	for _, stackTrace := range GetStackTraces(got) {
+		stackTraces = append(stackTraces, stackTrace)
+	}
+	if len(stackTraces) != 1 || stackTraces[0].String() != want[0].String() {
+		t.Errorf("GetStackTraces() = %v, want %v", stackTraces, want)
+	}
+}
+
+func firstStackTrace(err error) (StackTrace, bool) {
+	for _, stackTrace := range GetStackTraces(err) {
+		return stackTrace, true
+	}
+	return nil, false
+}
@@ -0,0 +1,172 @@
+// Package httperr provides net/http integration for errors created by serrors.
+package httperr
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/Siroshun09/serrors"
+)
+
+// Logger logs errors that occurred while handling requests.
+//
+// logs.Logger (including the one created by errorlogs) implements this interface.
+type Logger interface {
+	Error(ctx context.Context, err error)
+}
+
+// Option is the option for Middleware and Handle.
+type Option struct {
+	// Logger is the Logger for server errors (5xx).
+	//
+	// If nil, errors are logged by slog.Default.
+	Logger Logger
+	// Development is whether to render an HTML page that shows the error chain and its StackTraces.
+	//
+	// If false, the response only contains the status text, so the details of errors are not exposed.
+	// This should not be enabled in production.
+	Development bool
+}
+
+// HandlerFunc is a handler that returns an error.
+//
+// HandlerFunc implements http.Handler with the zero Option.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls f(w, r) and handles its error in the same way as Handle.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	Handle(f, Option{}).ServeHTTP(w, r)
+}
+
+// Middleware returns an http.Handler that recovers panics in next.
+//
+// A panic is converted into an error by serrors.Recover, so it has the StackTrace of the panic site.
+// The error is logged and written to the response in the same way as Handle.
+// The panic of http.ErrAbortHandler is not recovered, to abort the response as net/http does.
+func Middleware(next http.Handler, opt Option) http.Handler {
+	return Handle(func(w http.ResponseWriter, r *http.Request) error {
+		next.ServeHTTP(w, r)
+		return nil
+	}, opt)
+}
+
+// Handle returns an http.Handler that calls fn and handles its error.
+//
+// If fn returns an error or panics, the error is written to the response with the status code returned by StatusCode.
+// Server errors (5xx) are also logged by Option.Logger.
+// If fn has already written the response header, only logging is done.
+func Handle(fn HandlerFunc, opt Option) http.Handler {
+	logger := opt.Logger
+	if logger == nil {
+		logger = slogLogger{}
+	}
+	return &handler{fn: fn, logger: logger, development: opt.Development}
+}
+
+type handler struct {
+	fn          HandlerFunc
+	logger      Logger
+	development bool
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rw := &responseWriter{ResponseWriter: w}
+	err := h.serve(rw, r)
+	if err == nil {
+		return
+	}
+
+	if errors.Is(err, http.ErrAbortHandler) {
+		panic(http.ErrAbortHandler)
+	}
+
+	code := StatusCode(err)
+	if 500 <= code {
+		h.logger.Error(r.Context(), err)
+	}
+
+	if rw.wroteHeader {
+		return
+	}
+
+	if h.development {
+		writeDevelopmentPage(w, r, code, err)
+	} else {
+		writeStatusText(w, code)
+	}
+}
+
+func (h *handler) serve(w http.ResponseWriter, r *http.Request) (err error) {
+	defer serrors.Recover(&err)
+	return h.fn(w, r)
+}
+
+func writeStatusText(w http.ResponseWriter, code int) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+	_, _ = w.Write([]byte(http.StatusText(code) + "\n"))
+}
+
+type slogLogger struct{}
+
+func (slogLogger) Error(ctx context.Context, err error) {
+	slog.ErrorContext(ctx, "failed to handle the request", "error", err)
+}
+
+// responseWriter records whether the response header has been written.
+//
+// It implements http.Flusher, http.Hijacker and io.ReaderFrom by calling the original http.ResponseWriter,
+// so that handlers that assert them, such as streaming responses and WebSocket upgraders, keep working.
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher.
+//
+// If the original http.ResponseWriter does not support flushing, this method does nothing,
+// and the error page can still be written.
+func (w *responseWriter) Flush() {
+	if http.NewResponseController(w.ResponseWriter).Flush() == nil {
+		w.wroteHeader = true
+	}
+}
+
+// Hijack implements http.Hijacker.
+//
+// If the original http.ResponseWriter does not support hijacking, this method returns an error that wraps http.ErrNotSupported.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		// the connection is taken over by the handler, so the error page must not be written
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// ReadFrom implements io.ReaderFrom, so io.Copy can use the optimized implementation of the original http.ResponseWriter.
+func (w *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.wroteHeader = true
+	return io.Copy(w.ResponseWriter, r)
+}
+
+// Unwrap returns the original http.ResponseWriter for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,321 @@
+package httperr_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+	"github.com/Siroshun09/serrors/httperr"
+)
+
+type recordingLogger struct {
+	errs []error
+}
+
+func (l *recordingLogger) Error(_ context.Context, err error) {
+	l.errs = append(l.errs, err)
+}
+
+func serve(handler http.Handler) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test?q=1", nil))
+	return rec
+}
+
+func TestHandle(t *testing.T) {
+	tests := []struct {
+		name       string
+		fn         httperr.HandlerFunc
+		wantCode   int
+		wantBody   string
+		wantLogged bool
+		wantPanic  bool
+	}{
+		{
+			name: "no error",
+			fn: func(w http.ResponseWriter, r *http.Request) error {
+				_, _ = w.Write([]byte("ok"))
+				return nil
+			},
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+		},
+		{
+			name: "error",
+			fn: func(w http.ResponseWriter, r *http.Request) error {
+				return serrors.New("secret")
+			},
+			wantCode:   http.StatusInternalServerError,
+			wantBody:   "Internal Server Error\n",
+			wantLogged: true,
+		},
+		{
+			name: "client error",
+			fn: func(w http.ResponseWriter, r *http.Request) error {
+				return httperr.WithStatus(errors.New("secret"), http.StatusNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantBody: "Not Found\n",
+		},
+		{
+			name: "panic",
+			fn: func(w http.ResponseWriter, r *http.Request) error {
+				panic("secret")
+			},
+			wantCode:   http.StatusInternalServerError,
+			wantBody:   "Internal Server Error\n",
+			wantLogged: true,
+			wantPanic:  true,
+		},
+		{
+			name: "error after writing header",
+			fn: func(w http.ResponseWriter, r *http.Request) error {
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte("partial"))
+				return errors.New("secret")
+			},
+			wantCode:   http.StatusAccepted,
+			wantBody:   "partial",
+			wantLogged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			rec := serve(httperr.Handle(tt.fn, httperr.Option{Logger: logger}))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("Code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if rec.Body.String() != tt.wantBody {
+				t.Errorf("Body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+			if (len(logger.errs) == 1) != tt.wantLogged || 1 < len(logger.errs) {
+				t.Fatalf("logged errors = %v, wantLogged %v", logger.errs, tt.wantLogged)
+			}
+
+			var panicErr *serrors.PanicError
+			if tt.wantLogged && errors.As(logger.errs[0], &panicErr) != tt.wantPanic {
+				t.Errorf("logged error = %v, want PanicError: %v", logger.errs[0], tt.wantPanic)
+			}
+		})
+	}
+}
+
+func TestHandle_Development(t *testing.T) {
+	handler := httperr.Handle(func(w http.ResponseWriter, r *http.Request) error {
+		return serrors.With(serrors.New("<script>alert(1)</script>"), "key", "value")
+	}, httperr.Option{Logger: &recordingLogger{}, Development: true})
+
+	rec := serve(handler)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Code = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want text/html", got)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"<title>500 Internal Server Error</title>",
+		"<code>GET /test?q=1</code>",
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+		"*errors.errorString",
+		"httperr_test.TestHandle_Development.func1",
+		`<pre class="source">`,
+		`<mark>`,
+		"return serrors.With(serrors.New(&#34;&lt;script&gt;",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Body does not contain %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "<script>") {
+		t.Errorf("Body contains unescaped message:\n%s", body)
+	}
+}
+
+func TestHandle_Development_truncated(t *testing.T) {
+	prev := serrors.MaxStackDepth()
+	serrors.SetMaxStackDepth(1)
+	t.Cleanup(func() { serrors.SetMaxStackDepth(prev) })
+
+	handler := httperr.Handle(func(w http.ResponseWriter, r *http.Request) error {
+		return serrors.New("test")
+	}, httperr.Option{Logger: &recordingLogger{}, Development: true})
+
+	if body := serve(handler).Body.String(); !strings.Contains(body, `<td colspan="2" class="file">... more frames</td>`) {
+		t.Errorf("Body does not contain the omitted frames:\n%s", body)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	logger := &recordingLogger{}
+	handler := httperr.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m map[string]int
+		m["key"] = 1
+	}), httperr.Option{Logger: logger})
+
+	rec := serve(handler)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Code = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if len(logger.errs) != 1 {
+		t.Fatalf("logged errors = %v, want 1 error", logger.errs)
+	}
+
+	var panicErr *serrors.PanicError
+	if !errors.As(logger.errs[0], &panicErr) || panicErr.Kind != serrors.PanicKindNilMapAssignment {
+		t.Errorf("logged error = %v, want PanicError of nil map assignment", logger.errs[0])
+	}
+	if stackTrace := serrors.GetStackTrace(logger.errs[0]); !strings.HasSuffix(stackTrace[0].Name, "TestMiddleware.func1") {
+		t.Errorf("StackTrace()[0] = %s, want the panic site", stackTrace[0])
+	}
+
+	t.Run("no panic", func(t *testing.T) {
+		rec := serve(httperr.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}), httperr.Option{}))
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("Code = %d, want %d", rec.Code, http.StatusNoContent)
+		}
+	})
+
+	t.Run("ErrAbortHandler", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != http.ErrAbortHandler {
+				t.Errorf("recover() = %v, want http.ErrAbortHandler", r)
+			}
+		}()
+		serve(httperr.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}), httperr.Option{}))
+	})
+}
+
+// nonFlushingWriter hides the optional interfaces of the wrapped http.ResponseWriter, such as http.Flusher.
+type nonFlushingWriter struct {
+	w http.ResponseWriter
+}
+
+func (w nonFlushingWriter) Header() http.Header {
+	return w.w.Header()
+}
+
+func (w nonFlushingWriter) Write(b []byte) (int, error) {
+	return w.w.Write(b)
+}
+
+func (w nonFlushingWriter) WriteHeader(code int) {
+	w.w.WriteHeader(code)
+}
+
+func TestMiddleware_responseWriter(t *testing.T) {
+	t.Run("Flusher", func(t *testing.T) {
+		rec := serve(httperr.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			flusher, ok := w.(http.Flusher)
+			if !ok {
+				t.Fatal("ResponseWriter does not implement http.Flusher")
+			}
+			flusher.Flush()
+		}), httperr.Option{}))
+		if !rec.Flushed {
+			t.Error("Flushed = false, want true")
+		}
+	})
+
+	t.Run("Flusher not supported", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler := httperr.Handle(func(w http.ResponseWriter, r *http.Request) error {
+			w.(http.Flusher).Flush()
+			return errors.New("test")
+		}, httperr.Option{Logger: &recordingLogger{}})
+		handler.ServeHTTP(nonFlushingWriter{rec}, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Code = %d, want %d", rec.Code, http.StatusInternalServerError)
+		}
+		if rec.Body.Len() == 0 {
+			t.Error("Body is empty, want the error page")
+		}
+	})
+
+	t.Run("ResponseController", func(t *testing.T) {
+		rec := serve(httperr.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := http.NewResponseController(w).Flush(); err != nil {
+				t.Errorf("Flush() = %v, want nil", err)
+			}
+		}), httperr.Option{}))
+		if !rec.Flushed {
+			t.Error("Flushed = false, want true")
+		}
+	})
+
+	t.Run("ReaderFrom", func(t *testing.T) {
+		rec := serve(httperr.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := w.(io.ReaderFrom).ReadFrom(strings.NewReader("body")); err != nil {
+				t.Errorf("ReadFrom() = %v, want nil", err)
+			}
+		}), httperr.Option{}))
+		if rec.Body.String() != "body" {
+			t.Errorf("Body = %q, want %q", rec.Body.String(), "body")
+		}
+	})
+
+	t.Run("Hijacker", func(t *testing.T) {
+		server := httptest.NewServer(httperr.Handle(func(w http.ResponseWriter, r *http.Request) error {
+			conn, rw, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			_, _ = rw.WriteString("HTTP/1.1 418 I'm a teapot\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+			_ = rw.Flush()
+			return errors.New("written after hijacking")
+		}, httperr.Option{Logger: &recordingLogger{}}))
+		defer server.Close()
+
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusTeapot {
+			t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusTeapot)
+		}
+	})
+
+	t.Run("Hijacker not supported", func(t *testing.T) {
+		serve(httperr.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, _, err := w.(http.Hijacker).Hijack(); !errors.Is(err, http.ErrNotSupported) {
+				t.Errorf("Hijack() = %v, want http.ErrNotSupported", err)
+			}
+		}), httperr.Option{}))
+	})
+}
+
+func TestHandlerFunc_ServeHTTP(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	rec := serve(httperr.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("test")
+	}))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Code = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if !strings.Contains(buf.String(), "error=test") {
+		t.Errorf("slog output = %q, want error=test", buf.String())
+	}
+}
@@ -0,0 +1,137 @@
+package httperr
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Siroshun09/serrors"
+)
+
+var developmentPage = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Status}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+pre, code { font-family: monospace; }
+ol.chain li { margin-bottom: 0.5em; }
+table { border-collapse: collapse; }
+td { padding: 0.1em 1em 0.1em 0; vertical-align: top; }
+.type, .file { color: #666; }
+pre.source { margin: 0 0 0.5em 1em; color: #666; }
+pre.source mark { color: #000; }
+</style>
+</head>
+<body>
+<h1>{{.Status}}</h1>
+<p><code>{{.Method}} {{.URL}}</code></p>
+<h2>Error</h2>
+<pre>{{.Message}}</pre>
+<h2>Chain</h2>
+<ol class="chain">
+{{- range .Chain}}
+<li><pre>{{.Message}}</pre><span class="type">{{.Type}}</span></li>
+{{- end}}
+</ol>
+{{- range .StackTraces}}
+<h2>Stack trace</h2>
+<pre>{{.Message}}</pre>
+<table>
+{{- range .Frames}}
+<tr><td><code>{{.Name}}</code></td><td class="file"><code>{{.File}}:{{.Line}}</code></td></tr>
+{{- if .Source}}
+<tr><td colspan="2"><pre class="source">
+{{- range .Source}}
+{{if .Current}}<mark>{{printf "%4d" .Number}} | {{.Text}}</mark>{{else}}{{printf "%4d" .Number}} | {{.Text}}{{end}}
+{{- end}}
+</pre></td></tr>
+{{- end}}
+{{- end}}
+{{- if .Omitted}}
+<tr><td colspan="2" class="file">{{.Omitted}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+type developmentPageData struct {
+	Status      string
+	Method      string
+	URL         string
+	Message     string
+	Chain       []chainEntry
+	StackTraces []stackTraceEntry
+}
+
+type chainEntry struct {
+	Message string
+	Type    string
+}
+
+type stackTraceEntry struct {
+	Message string
+	Frames  []frameEntry
+	// Omitted is "... N more frames" if the stack trace is truncated, or empty.
+	Omitted string
+}
+
+type frameEntry struct {
+	serrors.FuncInfo
+	// Source is the source code around the frame, which is empty if the file is not available.
+	Source []serrors.SourceLine
+}
+
+// developmentSourceContext is the number of source lines shown before and after each frame.
+const developmentSourceContext = 3
+
+func writeDevelopmentPage(w http.ResponseWriter, r *http.Request, code int, err error) {
+	data := developmentPageData{
+		Status:  strconv.Itoa(code) + " " + http.StatusText(code),
+		Method:  r.Method,
+		URL:     r.URL.String(),
+		Message: err.Error(),
+		Chain:   appendChain(nil, serrors.Tree(err)),
+	}
+
+	for entry := range serrors.GetStackTraceEntries(err) {
+		stackTrace := entry.StackTrace.FilterDefault()
+		frames := make([]frameEntry, len(stackTrace))
+		for i, funcInfo := range stackTrace {
+			frames[i] = frameEntry{FuncInfo: funcInfo, Source: funcInfo.Source(developmentSourceContext)}
+		}
+		data.StackTraces = append(data.StackTraces, stackTraceEntry{
+			Message: entry.Err.Error(),
+			Frames:  frames,
+			Omitted: serrors.FormatOmittedFrames(entry.Omitted),
+		})
+	}
+
+	var b strings.Builder
+	if execErr := developmentPage.Execute(&b, data); execErr != nil {
+		writeStatusText(w, code)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+	_, _ = w.Write([]byte(b.String()))
+}
+
+// appendChain appends the messages and types of the nodes in depth-first order.
+func appendChain(chain []chainEntry, node *serrors.Node) []chainEntry {
+	if node == nil {
+		return chain
+	}
+
+	chain = append(chain, chainEntry{Message: node.Message, Type: node.Type})
+	for _, child := range node.Children {
+		chain = appendChain(chain, child)
+	}
+	return chain
+}
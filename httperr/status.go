@@ -0,0 +1,48 @@
+package httperr
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Siroshun09/serrors"
+)
+
+type statusError struct {
+	err  error
+	code int
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+func (e *statusError) StatusCode() int {
+	return e.code
+}
+
+// WithStatus returns an error that has the HTTP status code.
+//
+// If err does not have a StackTrace, the current StackTrace is attached.
+// If err is nil, this function returns nil.
+func WithStatus(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return serrors.WithStackTraceSkip(1, &statusError{err: err, code: code})
+}
+
+// StatusCode returns the HTTP status code of err.
+//
+// The status code is taken from the first error in the chain that has a StatusCode() int method, such as the errors returned by WithStatus.
+// If no error has the status code, this function returns http.StatusInternalServerError.
+func StatusCode(err error) int {
+	var coder interface{ StatusCode() int }
+	if errors.As(err, &coder) {
+		return coder.StatusCode()
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,74 @@
+package httperr_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+	"github.com/Siroshun09/serrors/httperr"
+)
+
+func TestWithStatus(t *testing.T) {
+	if err := httperr.WithStatus(nil, http.StatusNotFound); err != nil {
+		t.Errorf("WithStatus(nil) = %v, want nil", err)
+	}
+
+	base := errors.New("test")
+	err := httperr.WithStatus(base, http.StatusNotFound)
+	if err.Error() != "test" {
+		t.Errorf("Error() = %q, want test", err.Error())
+	}
+	if !errors.Is(err, base) {
+		t.Errorf("errors.Is(err, base) = false, want true")
+	}
+	stackTrace, ok := serrors.GetAttachedStackTrace(err)
+	if !ok {
+		t.Fatalf("WithStatus() does not have a StackTrace")
+	}
+	if want := "github.com/Siroshun09/serrors/httperr_test.TestWithStatus"; stackTrace[0].Name != want {
+		t.Errorf("StackTrace[0] = %s, want %s", stackTrace[0].Name, want)
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: http.StatusInternalServerError,
+		},
+		{
+			name: "no status code",
+			err:  errors.New("test"),
+			want: http.StatusInternalServerError,
+		},
+		{
+			name: "with status",
+			err:  httperr.WithStatus(errors.New("test"), http.StatusBadRequest),
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("wrap: %w", httperr.WithStatus(errors.New("test"), http.StatusConflict)),
+			want: http.StatusConflict,
+		},
+		{
+			name: "outermost status",
+			err:  httperr.WithStatus(httperr.WithStatus(errors.New("test"), http.StatusConflict), http.StatusForbidden),
+			want: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := httperr.StatusCode(tt.err); got != tt.want {
+				t.Errorf("StatusCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package serrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+type funcInfoJSON struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// FuncInfo is encoded as {"name": "...", "file": "...", "line": 0}.
+func (s FuncInfo) MarshalJSON() ([]byte, error) {
+	return json.Marshal(funcInfoJSON(s))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *FuncInfo) UnmarshalJSON(data []byte) error {
+	var v funcInfoJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = FuncInfo(v)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+//
+// FuncInfo is encoded in the same format as FuncInfo.String.
+func (s FuncInfo) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+//
+// The text must be in the format produced by FuncInfo.String: "name (file:line)".
+func (s *FuncInfo) UnmarshalText(text []byte) error {
+	// Function names never contain spaces, so the first " (" separates the name from the location.
+	nameEnd := bytes.Index(text, []byte(" ("))
+	if nameEnd < 0 || !bytes.HasSuffix(text, []byte(")")) {
+		return fmt.Errorf("invalid FuncInfo format: %q", text)
+	}
+
+	location := text[nameEnd+2 : len(text)-1]
+	lineStart := bytes.LastIndexByte(location, ':')
+	if lineStart < 0 {
+		return fmt.Errorf("invalid FuncInfo format: %q", text)
+	}
+
+	line, err := strconv.Atoi(string(location[lineStart+1:]))
+	if err != nil {
+		return fmt.Errorf("invalid FuncInfo line number: %q: %w", text, err)
+	}
+
+	*s = FuncInfo{
+		Name: string(text[:nameEnd]),
+		File: string(location[:lineStart]),
+		Line: line,
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// StackTrace is encoded as an array of FuncInfo.
+func (s StackTrace) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]FuncInfo(s))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *StackTrace) UnmarshalJSON(data []byte) error {
+	var v []FuncInfo
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+//
+// StackTrace is encoded in the same format as StackTrace.String.
+func (s StackTrace) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+//
+// The text must be in the format produced by StackTrace.String.
+func (s *StackTrace) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*s = StackTrace{}
+		return nil
+	}
+
+	lines := bytes.Split(text, []byte("\n"))
+	st := make(StackTrace, len(lines))
+	for i, line := range lines {
+		if err := st[i].UnmarshalText(line); err != nil {
+			return err
+		}
+	}
+	*s = st
+	return nil
+}
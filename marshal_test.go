@@ -0,0 +1,258 @@
+package serrors
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFuncInfo_JSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		funcInfo FuncInfo
+		want     string
+	}{
+		{
+			name:     "success",
+			funcInfo: FuncInfo{Name: "pkg.(*T).Test", File: "/path/to/test.go", Line: 10},
+			want:     `{"name":"pkg.(*T).Test","file":"/path/to/test.go","line":10}`,
+		},
+		{
+			name:     "zero",
+			funcInfo: FuncInfo{},
+			want:     `{"name":"","file":"","line":0}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.funcInfo)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("MarshalJSON() = %s, want %s", data, tt.want)
+			}
+
+			var got FuncInfo
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if got != tt.funcInfo {
+				t.Errorf("UnmarshalJSON() = %v, want %v", got, tt.funcInfo)
+			}
+			if got.String() != tt.funcInfo.String() {
+				t.Errorf("String() = %v, want %v", got.String(), tt.funcInfo.String())
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		var got FuncInfo
+		if err := json.Unmarshal([]byte(`{"line":"1"}`), &got); err == nil {
+			t.Errorf("UnmarshalJSON() = %v, want error", got)
+		}
+	})
+}
+
+func TestFuncInfo_Text(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    FuncInfo
+		wantErr bool
+	}{
+		{
+			name: "success",
+			text: "Test (test.go:10)",
+			want: FuncInfo{Name: "Test", File: "test.go", Line: 10},
+		},
+		{
+			name: "method and closure",
+			text: "github.com/x/y/pkg.(*Server).handle.func2 (/path/to/server.go:42)",
+			want: FuncInfo{Name: "github.com/x/y/pkg.(*Server).handle.func2", File: "/path/to/server.go", Line: 42},
+		},
+		{
+			name: "windows path",
+			text: `pkg.Test (C:\path\to\test.go:3)`,
+			want: FuncInfo{Name: "pkg.Test", File: `C:\path\to\test.go`, Line: 3},
+		},
+		{
+			name: "path with parentheses and spaces",
+			text: "pkg.Test (/path/my project (copy)/test.go:3)",
+			want: FuncInfo{Name: "pkg.Test", File: "/path/my project (copy)/test.go", Line: 3},
+		},
+		{
+			name: "zero",
+			text: " (:0)",
+			want: FuncInfo{},
+		},
+		{
+			name:    "no location",
+			text:    "Test",
+			wantErr: true,
+		},
+		{
+			name:    "no line number",
+			text:    "Test (test.go)",
+			wantErr: true,
+		},
+		{
+			name:    "invalid line number",
+			text:    "Test (test.go:a)",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got FuncInfo
+			err := got.UnmarshalText([]byte(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalText() = %v, want %v", got, tt.want)
+			}
+
+			text, err := got.MarshalText()
+			if err != nil {
+				t.Fatalf("MarshalText() error = %v", err)
+			}
+			if string(text) != tt.text || string(text) != got.String() {
+				t.Errorf("MarshalText() = %s, want %s", text, tt.text)
+			}
+		})
+	}
+}
+
+func TestStackTrace_JSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		stackTrace StackTrace
+		want       string
+	}{
+		{
+			name:       "nil",
+			stackTrace: nil,
+			want:       `null`,
+		},
+		{
+			name:       "empty",
+			stackTrace: StackTrace{},
+			want:       `[]`,
+		},
+		{
+			name: "2 FuncInfo",
+			stackTrace: StackTrace{
+				{Name: "Test1", File: "test.go", Line: 1},
+				{Name: "Test2", File: "test.go", Line: 2},
+			},
+			want: `[{"name":"Test1","file":"test.go","line":1},{"name":"Test2","file":"test.go","line":2}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.stackTrace)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("MarshalJSON() = %s, want %s", data, tt.want)
+			}
+
+			var got StackTrace
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.stackTrace) {
+				t.Errorf("UnmarshalJSON() = %#v, want %#v", got, tt.stackTrace)
+			}
+			if got.String() != tt.stackTrace.String() {
+				t.Errorf("String() = %v, want %v", got.String(), tt.stackTrace.String())
+			}
+		})
+	}
+
+	t.Run("captured stack trace", func(t *testing.T) {
+		stackTrace := GetCurrentStackTrace()
+		data, err := json.Marshal(stackTrace)
+		if err != nil {
+			t.Fatalf("MarshalJSON() error = %v", err)
+		}
+
+		var got StackTrace
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("UnmarshalJSON() error = %v", err)
+		}
+		if got.String() != stackTrace.String() {
+			t.Errorf("String() = %v, want %v", got.String(), stackTrace.String())
+		}
+	})
+}
+
+func TestStackTrace_Text(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    StackTrace
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			text: "",
+			want: StackTrace{},
+		},
+		{
+			name: "1 FuncInfo",
+			text: "Test (test.go:1)",
+			want: StackTrace{{Name: "Test", File: "test.go", Line: 1}},
+		},
+		{
+			name: "2 FuncInfo",
+			text: "Test1 (test.go:1)\nTest2 (test.go:2)",
+			want: StackTrace{{Name: "Test1", File: "test.go", Line: 1}, {Name: "Test2", File: "test.go", Line: 2}},
+		},
+		{
+			name:    "invalid line",
+			text:    "Test1 (test.go:1)\nTest2",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got StackTrace
+			err := got.UnmarshalText([]byte(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnmarshalText() = %v, want %v", got, tt.want)
+			}
+
+			text, err := got.MarshalText()
+			if err != nil {
+				t.Fatalf("MarshalText() error = %v", err)
+			}
+			if string(text) != tt.text || string(text) != got.String() {
+				t.Errorf("MarshalText() = %s, want %s", text, tt.text)
+			}
+		})
+	}
+
+	t.Run("captured stack trace", func(t *testing.T) {
+		stackTrace := GetCurrentStackTrace()
+
+		var got StackTrace
+		if err := got.UnmarshalText([]byte(stackTrace.String())); err != nil {
+			t.Fatalf("UnmarshalText() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, stackTrace) {
+			t.Errorf("UnmarshalText() = %v, want %v", got, stackTrace)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package serrors
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// PanicKind classifies the value of a recovered panic.
+type PanicKind int8
+
+const (
+	// PanicKindValue indicates that panic was called with a value that is not a runtime.Error.
+	PanicKindValue PanicKind = iota
+	// PanicKindRuntimeError indicates a runtime.Error that is not classified by other kinds.
+	PanicKindRuntimeError
+	// PanicKindNilDereference indicates an invalid memory address or nil pointer dereference.
+	PanicKindNilDereference
+	// PanicKindIndexOutOfRange indicates an index out of range.
+	PanicKindIndexOutOfRange
+	// PanicKindSliceBoundsOutOfRange indicates slice bounds out of range.
+	PanicKindSliceBoundsOutOfRange
+	// PanicKindDivideByZero indicates an integer divide by zero.
+	PanicKindDivideByZero
+	// PanicKindNilMapAssignment indicates an assignment to entry in nil map.
+	PanicKindNilMapAssignment
+	// PanicKindTypeAssertion indicates a failed type assertion.
+	PanicKindTypeAssertion
+	// PanicKindNilPanic indicates that panic was called with nil.
+	PanicKindNilPanic
+)
+
+// String returns the name of the PanicKind.
+func (k PanicKind) String() string {
+	switch k {
+	case PanicKindValue:
+		return "value"
+	case PanicKindRuntimeError:
+		return "runtime error"
+	case PanicKindNilDereference:
+		return "nil dereference"
+	case PanicKindIndexOutOfRange:
+		return "index out of range"
+	case PanicKindSliceBoundsOutOfRange:
+		return "slice bounds out of range"
+	case PanicKindDivideByZero:
+		return "divide by zero"
+	case PanicKindNilMapAssignment:
+		return "nil map assignment"
+	case PanicKindTypeAssertion:
+		return "type assertion"
+	case PanicKindNilPanic:
+		return "nil panic"
+	default:
+		return "unknown"
+	}
+}
+
+// PanicError is an error created from a recovered panic.
+//
+// If the panic value is an error, PanicError wraps it, so errors.As can get the original value such as runtime.Error.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Kind is the classification of Value.
+	Kind PanicKind
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Recover converts a panic into an error that has a *PanicError and a StackTrace, and stores it to errp.
+//
+// The StackTrace is captured at the panic site, not the site of Recover.
+//
+// Recover must be called directly by defer, like "defer serrors.Recover(&err)".
+// Otherwise, recover returns nil and the panic is not stopped.
+//
+// If no panic occurs, errp is not modified.
+func Recover(errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	*errp = newPanicError(r)
+}
+
+// Catch calls fn and returns its error.
+//
+// If fn panics, Catch recovers it and returns an error in the same way as Recover.
+func Catch(fn func() error) (err error) {
+	defer Recover(&err)
+	return fn()
+}
+
+func newPanicError(value any) error {
+	// the whole stack is captured, so that the panic site is found even if the stack is deeper than the max depth
+	pcs, _ := callers(1, 0) // newPanicError
+	stackTrace := panicSiteStackTrace(newStackTraceFromPCs(pcs))
+
+	omitted := 0
+	if depth := MaxStackDepth(); 0 < depth && depth < len(stackTrace) {
+		omitted = len(stackTrace) - depth
+		stackTrace = slices.Clone(stackTrace[:depth])
+	}
+
+	return &stackTraceError{
+		err: &PanicError{
+			Value: value,
+			Kind:  classifyPanic(value),
+		},
+		omitted:    omitted,
+		stackTrace: stackTrace,
+	}
+}
+
+// panicSiteStackTrace drops the frames of the deferred function and the runtime from a StackTrace captured while panicking.
+func panicSiteStackTrace(stackTrace StackTrace) StackTrace {
+	// the innermost runtime.gopanic raised the panic being recovered
+	start := slices.IndexFunc(stackTrace, func(funcInfo FuncInfo) bool {
+		return funcInfo.Name == "runtime.gopanic"
+	}) + 1
+	if start == 0 {
+		return stackTrace
+	}
+
+	// skip runtime frames that raise runtime errors, such as runtime.sigpanic and runtime.panicIndex
+	for start < len(stackTrace) && strings.HasPrefix(stackTrace[start].Name, "runtime.") {
+		start++
+	}
+	return stackTrace[start:]
+}
+
+func classifyPanic(value any) PanicKind {
+	runtimeErr, ok := value.(runtime.Error)
+	if !ok {
+		return PanicKindValue
+	}
+
+	var nilPanicErr *runtime.PanicNilError
+	var typeAssertionErr *runtime.TypeAssertionError
+	msg := runtimeErr.Error()
+	switch {
+	case errors.As(runtimeErr, &nilPanicErr):
+		return PanicKindNilPanic
+	case errors.As(runtimeErr, &typeAssertionErr):
+		return PanicKindTypeAssertion
+	case strings.Contains(msg, "nil pointer dereference"):
+		return PanicKindNilDereference
+	case strings.Contains(msg, "index out of range"):
+		return PanicKindIndexOutOfRange
+	case strings.Contains(msg, "slice bounds out of range"):
+		return PanicKindSliceBoundsOutOfRange
+	case strings.Contains(msg, "integer divide by zero"):
+		return PanicKindDivideByZero
+	case strings.Contains(msg, "assignment to entry in nil map"):
+		return PanicKindNilMapAssignment
+	default:
+		return PanicKindRuntimeError
+	}
+}
@@ -0,0 +1,243 @@
+package serrors
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+)
+
+//go:noinline
+func panicWithValue(value any) {
+	panic(value)
+}
+
+//go:noinline
+func panicWithNilDereference() {
+	var p *int
+	_ = *p
+}
+
+//go:noinline
+func panicWithIndexOutOfRange(i int) {
+	var s []int
+	_ = s[i]
+}
+
+//go:noinline
+func panicWithSliceBoundsOutOfRange(i int) {
+	var s []int
+	_ = s[:i]
+}
+
+//go:noinline
+func panicWithDivideByZero(i int) {
+	_ = 1 / i
+}
+
+//go:noinline
+func panicWithNilMapAssignment() {
+	var m map[string]int
+	m["key"] = 1
+}
+
+//go:noinline
+func panicWithTypeAssertion(v any) {
+	_ = v.(string)
+}
+
+func TestCatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		fn           func() error
+		wantKind     PanicKind
+		wantValue    any
+		wantOrigin   string
+		wantRuntime  bool
+		wantErrorMsg string
+	}{
+		{
+			name:         "value",
+			fn:           func() error { panicWithValue("test"); return nil },
+			wantKind:     PanicKindValue,
+			wantValue:    "test",
+			wantOrigin:   "github.com/Siroshun09/serrors.panicWithValue",
+			wantErrorMsg: "panic: test",
+		},
+		{
+			name:         "error value",
+			fn:           func() error { panicWithValue(io.EOF); return nil },
+			wantKind:     PanicKindValue,
+			wantValue:    io.EOF,
+			wantOrigin:   "github.com/Siroshun09/serrors.panicWithValue",
+			wantErrorMsg: "panic: EOF",
+		},
+		{
+			name:        "nil",
+			fn:          func() error { panicWithValue(nil); return nil },
+			wantKind:    PanicKindNilPanic,
+			wantOrigin:  "github.com/Siroshun09/serrors.panicWithValue",
+			wantRuntime: true,
+		},
+		{
+			name:         "nil dereference",
+			fn:           func() error { panicWithNilDereference(); return nil },
+			wantKind:     PanicKindNilDereference,
+			wantOrigin:   "github.com/Siroshun09/serrors.panicWithNilDereference",
+			wantRuntime:  true,
+			wantErrorMsg: "panic: runtime error: invalid memory address or nil pointer dereference",
+		},
+		{
+			name:         "index out of range",
+			fn:           func() error { panicWithIndexOutOfRange(1); return nil },
+			wantKind:     PanicKindIndexOutOfRange,
+			wantOrigin:   "github.com/Siroshun09/serrors.panicWithIndexOutOfRange",
+			wantRuntime:  true,
+			wantErrorMsg: "panic: runtime error: index out of range [1] with length 0",
+		},
+		{
+			name:         "slice bounds out of range",
+			fn:           func() error { panicWithSliceBoundsOutOfRange(1); return nil },
+			wantKind:     PanicKindSliceBoundsOutOfRange,
+			wantOrigin:   "github.com/Siroshun09/serrors.panicWithSliceBoundsOutOfRange",
+			wantRuntime:  true,
+			wantErrorMsg: "panic: runtime error: slice bounds out of range [:1] with capacity 0",
+		},
+		{
+			name:         "divide by zero",
+			fn:           func() error { panicWithDivideByZero(0); return nil },
+			wantKind:     PanicKindDivideByZero,
+			wantOrigin:   "github.com/Siroshun09/serrors.panicWithDivideByZero",
+			wantRuntime:  true,
+			wantErrorMsg: "panic: runtime error: integer divide by zero",
+		},
+		{
+			name:         "nil map assignment",
+			fn:           func() error { panicWithNilMapAssignment(); return nil },
+			wantKind:     PanicKindNilMapAssignment,
+			wantOrigin:   "github.com/Siroshun09/serrors.panicWithNilMapAssignment",
+			wantRuntime:  true,
+			wantErrorMsg: "panic: assignment to entry in nil map",
+		},
+		{
+			name:         "type assertion",
+			fn:           func() error { panicWithTypeAssertion(1); return nil },
+			wantKind:     PanicKindTypeAssertion,
+			wantOrigin:   "github.com/Siroshun09/serrors.panicWithTypeAssertion",
+			wantRuntime:  true,
+			wantErrorMsg: "panic: interface conversion: interface {} is int, not string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Catch(tt.fn)
+			if err == nil {
+				t.Fatalf("Catch() = nil, want error")
+			}
+			if tt.wantErrorMsg != "" && err.Error() != tt.wantErrorMsg {
+				t.Errorf("Catch().Error() = %q, want %q", err.Error(), tt.wantErrorMsg)
+			}
+
+			var panicErr *PanicError
+			if !errors.As(err, &panicErr) {
+				t.Fatalf("errors.As(Catch(), *PanicError) = false, want true")
+			}
+			if panicErr.Kind != tt.wantKind {
+				t.Errorf("PanicError.Kind = %v, want %v", panicErr.Kind, tt.wantKind)
+			}
+			if tt.wantValue != nil && panicErr.Value != tt.wantValue {
+				t.Errorf("PanicError.Value = %v, want %v", panicErr.Value, tt.wantValue)
+			}
+
+			var runtimeErr runtime.Error
+			if errors.As(err, &runtimeErr) != tt.wantRuntime {
+				t.Errorf("errors.As(Catch(), runtime.Error) = %v, want %v", !tt.wantRuntime, tt.wantRuntime)
+			}
+
+			stackTrace, ok := GetAttachedStackTrace(err)
+			if !ok || len(stackTrace) == 0 || stackTrace[0].Name != tt.wantOrigin {
+				t.Errorf("GetAttachedStackTrace() = (%v, %v), want to start with %s", stackTrace, ok, tt.wantOrigin)
+			}
+		})
+	}
+
+	t.Run("no panic", func(t *testing.T) {
+		want := errors.New("test")
+		if got := Catch(func() error { return want }); got != want {
+			t.Errorf("Catch() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestRecover(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		err := func() (err error) {
+			defer Recover(&err)
+			panicWithValue("test")
+			return nil
+		}()
+
+		var panicErr *PanicError
+		if !errors.As(err, &panicErr) || panicErr.Value != "test" {
+			t.Errorf("Recover() = %v, want *PanicError", err)
+		}
+	})
+
+	t.Run("no panic", func(t *testing.T) {
+		want := errors.New("test")
+		err := func() (err error) {
+			defer Recover(&err)
+			return want
+		}()
+
+		if err != want {
+			t.Errorf("Recover() = %v, want %v", err, want)
+		}
+	})
+}
+
+func Test_panicSiteStackTrace(t *testing.T) {
+	tests := []struct {
+		name       string
+		stackTrace StackTrace
+		want       StackTrace
+	}{
+		{
+			name:       "not panicking",
+			stackTrace: StackTrace{{Name: "main.main"}},
+			want:       StackTrace{{Name: "main.main"}},
+		},
+		{
+			name:       "panic",
+			stackTrace: StackTrace{{Name: "serrors.Recover"}, {Name: "runtime.gopanic"}, {Name: "main.f"}, {Name: "main.main"}},
+			want:       StackTrace{{Name: "main.f"}, {Name: "main.main"}},
+		},
+		{
+			name:       "runtime error",
+			stackTrace: StackTrace{{Name: "serrors.Recover"}, {Name: "runtime.gopanic"}, {Name: "runtime.panicmem"}, {Name: "runtime.sigpanic"}, {Name: "main.f"}},
+			want:       StackTrace{{Name: "main.f"}},
+		},
+		{
+			name:       "nested panic",
+			stackTrace: StackTrace{{Name: "serrors.Recover"}, {Name: "runtime.gopanic"}, {Name: "main.g"}, {Name: "runtime.gopanic"}, {Name: "main.f"}},
+			want:       StackTrace{{Name: "main.g"}, {Name: "runtime.gopanic"}, {Name: "main.f"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := panicSiteStackTrace(tt.stackTrace)
+			if got.String() != tt.want.String() {
+				t.Errorf("panicSiteStackTrace() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPanicKind_String(t *testing.T) {
+	if got := PanicKindNilDereference.String(); got != "nil dereference" {
+		t.Errorf("String() = %v, want nil dereference", got)
+	}
+	if got := PanicKind(-1).String(); got != "unknown" {
+		t.Errorf("String() = %v, want unknown", got)
+	}
+}
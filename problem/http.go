@@ -0,0 +1,131 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+
+	"github.com/Siroshun09/serrors"
+)
+
+// WriteOption is the option for Write.
+type WriteOption struct {
+	// Attributes is whether to write the attributes returned by serrors.Attributes as extension members.
+	//
+	// The attributes do not overwrite the extension members of the Problem.
+	Attributes bool
+	// Debug is whether to write the StackTraces returned by serrors.GetStackTraceEntries as the "stacktraces" extension member.
+	//
+	// Each element has "error" (the message), "stacktrace" (the StackTrace encoded as JSON),
+	// and "omitted" (the number of frames cut off by serrors.SetMaxStackDepth, only if the StackTrace is truncated).
+	// This should not be enabled in production.
+	Debug bool
+}
+
+type debugStackTrace struct {
+	Error      string             `json:"error"`
+	StackTrace serrors.StackTrace `json:"stacktrace"`
+	Omitted    int                `json:"omitted,omitempty"`
+}
+
+// Write writes err to w as problem details.
+//
+// The members are taken from the first Problem in the error chain.
+// If err does not have a Problem, only the status code and its status text are written, so the message of err is not exposed.
+// The status code is taken from the first error that has a StatusCode() int method (http.StatusInternalServerError by default).
+func Write(w http.ResponseWriter, err error, opt WriteOption) {
+	var doc Problem
+	var p *Problem
+	if errors.As(err, &p) {
+		doc = *p
+		doc.Extensions = maps.Clone(p.Extensions)
+	}
+	if doc.Status == 0 {
+		doc.Status = statusCode(err)
+	}
+	if doc.Type == "" && doc.Title == "" {
+		doc.Title = http.StatusText(doc.Status)
+	}
+
+	if opt.Attributes {
+		for _, attr := range serrors.Attributes(err) {
+			if _, ok := doc.Extensions[attr.Key]; !ok {
+				setExtension(&doc, attr.Key, serrors.AttrValue(attr.Value))
+			}
+		}
+	}
+
+	if opt.Debug {
+		var stackTraces []debugStackTrace
+		for entry := range serrors.GetStackTraceEntries(err) {
+			stackTraces = append(stackTraces, debugStackTrace{
+				Error:      entry.Err.Error(),
+				StackTrace: entry.StackTrace.FilterDefault(),
+				Omitted:    entry.Omitted,
+			})
+		}
+		if 0 < len(stackTraces) {
+			setExtension(&doc, "stacktraces", stackTraces)
+		}
+	}
+
+	body, marshalErr := json.Marshal(&doc)
+	if marshalErr != nil {
+		// the extension members cannot be encoded, so write only the standard members
+		doc.Extensions = nil
+		body, _ = json.Marshal(&doc)
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(doc.Status)
+	_, _ = w.Write(body)
+}
+
+func statusCode(err error) int {
+	var coder interface{ StatusCode() int }
+	if errors.As(err, &coder) {
+		return coder.StatusCode()
+	}
+	return http.StatusInternalServerError
+}
+
+func setExtension(p *Problem, key string, value any) {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[key] = value
+}
+
+// Parse reads the problem details from resp, and returns them as an error that has a *Problem.
+//
+// If the status code of resp is not an error (4xx or 5xx), this function returns nil.
+// If resp is not application/problem+json, the Problem only has the status code.
+// If the body cannot be parsed, the Problem only has the status code, and wraps the error that occurred while parsing it.
+//
+// The returned error has the StackTrace of the caller, and resp.Body is not closed.
+func Parse(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	p := &Problem{}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == ContentType {
+		body, err := io.ReadAll(resp.Body)
+		if err == nil {
+			err = json.Unmarshal(body, p)
+		}
+		if err != nil {
+			p = &Problem{err: fmt.Errorf("failed to parse problem details: %w", err)}
+		}
+	}
+
+	if p.Status == 0 {
+		p.Status = resp.StatusCode
+	}
+	return serrors.WithStackTraceSkip(1, p)
+}
@@ -0,0 +1,241 @@
+package problem_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+	"github.com/Siroshun09/serrors/problem"
+)
+
+type statusCodeError struct{}
+
+func (statusCodeError) Error() string   { return "secret" }
+func (statusCodeError) StatusCode() int { return http.StatusTeapot }
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		opt      problem.WriteOption
+		wantCode int
+		wantBody map[string]any
+	}{
+		{
+			name:     "problem",
+			err:      fmt.Errorf("wrap: %w", problem.New(http.StatusNotFound, "user 1 is not found")),
+			wantCode: http.StatusNotFound,
+			wantBody: map[string]any{"title": "Not Found", "status": float64(404), "detail": "user 1 is not found"},
+		},
+		{
+			name: "problem with type and extensions",
+			err: &problem.Problem{
+				Type:       "https://example.com/probs/out-of-credit",
+				Status:     http.StatusForbidden,
+				Extensions: map[string]any{"balance": 30},
+			},
+			wantCode: http.StatusForbidden,
+			wantBody: map[string]any{"type": "https://example.com/probs/out-of-credit", "status": float64(403), "balance": float64(30)},
+		},
+		{
+			name:     "not a problem",
+			err:      serrors.New("secret"),
+			wantCode: http.StatusInternalServerError,
+			wantBody: map[string]any{"title": "Internal Server Error", "status": float64(500)},
+		},
+		{
+			name:     "status code",
+			err:      statusCodeError{},
+			wantCode: http.StatusTeapot,
+			wantBody: map[string]any{"title": "I'm a teapot", "status": float64(418)},
+		},
+		{
+			name:     "attributes",
+			err:      serrors.With(problem.Wrap(errors.New("secret"), &problem.Problem{Status: http.StatusBadRequest, Extensions: map[string]any{"field": "name"}}), "field", "email", "user_id", 1),
+			opt:      problem.WriteOption{Attributes: true},
+			wantCode: http.StatusBadRequest,
+			wantBody: map[string]any{"title": "Bad Request", "status": float64(400), "field": "name", "user_id": float64(1)},
+		},
+		{
+			name:     "attributes / not enabled",
+			err:      serrors.With(problem.New(http.StatusBadRequest, ""), "user_id", 1),
+			wantCode: http.StatusBadRequest,
+			wantBody: map[string]any{"title": "Bad Request", "status": float64(400)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			problem.Write(rec, tt.err, tt.opt)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("Code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if got := rec.Header().Get("Content-Type"); got != problem.ContentType {
+				t.Errorf("Content-Type = %q, want %q", got, problem.ContentType)
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(body, tt.wantBody) {
+				t.Errorf("Body = %v, want %v", body, tt.wantBody)
+			}
+		})
+	}
+
+	t.Run("debug", func(t *testing.T) {
+		err := problem.New(http.StatusConflict, "conflict")
+		rec := httptest.NewRecorder()
+		problem.Write(rec, err, problem.WriteOption{Debug: true})
+
+		var body struct {
+			StackTraces []struct {
+				Error      string             `json:"error"`
+				StackTrace serrors.StackTrace `json:"stacktrace"`
+			} `json:"stacktraces"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		if len(body.StackTraces) != 1 {
+			t.Fatalf("stacktraces = %+v, want 1 element", body.StackTraces)
+		}
+		if body.StackTraces[0].Error != "Conflict: conflict" {
+			t.Errorf("error = %q, want %q", body.StackTraces[0].Error, "Conflict: conflict")
+		}
+		if !reflect.DeepEqual(body.StackTraces[0].StackTrace, serrors.GetStackTrace(err)) {
+			t.Errorf("stacktrace = %v, want %v", body.StackTraces[0].StackTrace, serrors.GetStackTrace(err))
+		}
+	})
+
+	t.Run("debug truncated", func(t *testing.T) {
+		err := serrors.Decode(&serrors.Envelope{
+			Message:    "test",
+			Kind:       serrors.EnvelopeKindStackTrace,
+			StackTrace: serrors.StackTrace{{Name: "example.com/app.handle", File: "/src/app/handler.go", Line: 10}},
+			Omitted:    3,
+			Causes:     []*serrors.Envelope{{Message: "test"}},
+		})
+		rec := httptest.NewRecorder()
+		problem.Write(rec, err, problem.WriteOption{Debug: true})
+
+		var body struct {
+			StackTraces []struct {
+				Omitted int `json:"omitted"`
+			} `json:"stacktraces"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		if len(body.StackTraces) != 1 || body.StackTraces[0].Omitted != 3 {
+			t.Errorf("stacktraces = %+v, want 1 element with omitted = 3", body.StackTraces)
+		}
+	})
+
+	t.Run("unsupported extension", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		problem.Write(rec, &problem.Problem{Status: http.StatusBadRequest, Extensions: map[string]any{"func": func() {}}}, problem.WriteOption{})
+
+		if got, want := rec.Body.String(), `{"status":400,"title":"Bad Request"}`; got != want {
+			t.Errorf("Body = %s, want %s", got, want)
+		}
+	})
+}
+
+func newResponse(code int, contentType, body string) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Header:     http.Header{"Content-Type": []string{contentType}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		resp      *http.Response
+		want      *problem.Problem
+		wantCause bool
+	}{
+		{
+			name: "success",
+			resp: newResponse(http.StatusOK, "application/json", `{}`),
+			want: nil,
+		},
+		{
+			name: "problem",
+			resp: newResponse(http.StatusForbidden, "application/problem+json; charset=utf-8", `{"type":"https://example.com/probs/out-of-credit","title":"Out of credit","detail":"detail","balance":30}`),
+			want: &problem.Problem{
+				Type:       "https://example.com/probs/out-of-credit",
+				Title:      "Out of credit",
+				Status:     http.StatusForbidden,
+				Detail:     "detail",
+				Extensions: map[string]any{"balance": float64(30)},
+			},
+		},
+		{
+			name: "not a problem",
+			resp: newResponse(http.StatusBadGateway, "text/html", `<html></html>`),
+			want: &problem.Problem{Status: http.StatusBadGateway},
+		},
+		{
+			name:      "invalid body",
+			resp:      newResponse(http.StatusBadRequest, problem.ContentType, `{`),
+			want:      &problem.Problem{Status: http.StatusBadRequest},
+			wantCause: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := problem.Parse(tt.resp)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Parse() = %v, want nil", err)
+				}
+				return
+			}
+
+			var got *problem.Problem
+			if !errors.As(err, &got) {
+				t.Fatalf("errors.As(Parse(), *Problem) = false, want true: %v", err)
+			}
+			if (got.Unwrap() != nil) != tt.wantCause {
+				t.Errorf("Unwrap() = %v, wantCause %v", got.Unwrap(), tt.wantCause)
+			}
+			if got.Type != tt.want.Type || got.Title != tt.want.Title || got.Status != tt.want.Status ||
+				got.Detail != tt.want.Detail || !reflect.DeepEqual(got.Extensions, tt.want.Extensions) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+			if _, ok := serrors.GetAttachedStackTrace(err); !ok {
+				t.Error("Parse() does not have a StackTrace")
+			}
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			problem.Write(w, problem.New(http.StatusNotFound, "user 1 is not found"), problem.WriteOption{})
+		}))
+		t.Cleanup(server.Close)
+
+		resp, err := server.Client().Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		err = problem.Parse(resp)
+		if err == nil || err.Error() != "Not Found: user 1 is not found" {
+			t.Errorf("Parse() = %v, want Not Found: user 1 is not found", err)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+// Package problem renders errors as RFC 9457 problem details (application/problem+json) and parses them.
+package problem
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/Siroshun09/serrors"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// Problem is an error that has the members of RFC 9457 problem details.
+type Problem struct {
+	// Type is the URI reference that identifies the problem type.
+	//
+	// If empty, the problem type is "about:blank", which means that the problem has no additional semantics beyond the status code.
+	Type string
+	// Title is the short, human-readable summary of the problem type.
+	Title string
+	// Status is the HTTP status code.
+	Status int
+	// Detail is the human-readable explanation specific to this occurrence of the problem.
+	Detail string
+	// Instance is the URI reference that identifies this occurrence of the problem.
+	Instance string
+	// Extensions are the extension members.
+	//
+	// The keys of the standard members are ignored when the Problem is encoded.
+	Extensions map[string]any
+
+	err error
+}
+
+// New creates a Problem with a StackTrace.
+//
+// The title is the status text of the status code.
+func New(status int, detail string) error {
+	return serrors.WithStackTraceSkip(1, &Problem{Title: http.StatusText(status), Status: status, Detail: detail})
+}
+
+// Wrap returns a copy of p that has err as its cause, with a StackTrace.
+//
+// p and its Extensions are not modified, so it can be a template shared by requests, such as a package-level variable.
+// The cause is not rendered as a member, so its message is not exposed to clients.
+// If p is nil, this function returns err.
+func Wrap(err error, p *Problem) error {
+	if p == nil {
+		return err
+	}
+
+	cp := *p
+	cp.Extensions = maps.Clone(p.Extensions)
+	cp.err = err
+	return serrors.WithStackTraceSkip(1, &cp)
+}
+
+// Error returns the title and the detail of the Problem.
+//
+// If the detail is empty, the message of the cause is used instead.
+func (p *Problem) Error() string {
+	title := p.Title
+	if title == "" {
+		title = http.StatusText(p.Status)
+	}
+
+	switch {
+	case p.Detail != "":
+		return title + ": " + p.Detail
+	case p.err != nil:
+		return title + ": " + p.err.Error()
+	default:
+		return title
+	}
+}
+
+// Unwrap returns the cause of the Problem set by Wrap.
+func (p *Problem) Unwrap() error {
+	return p.err
+}
+
+// StatusCode returns the HTTP status code of the Problem.
+//
+// If Status is not set, this method returns http.StatusInternalServerError.
+func (p *Problem) StatusCode() int {
+	if p.Status == 0 {
+		return http.StatusInternalServerError
+	}
+	return p.Status
+}
+
+var standardMembers = []string{"type", "title", "status", "detail", "instance"}
+
+// MarshalJSON implements json.Marshaler.
+//
+// The standard members are omitted if they are empty, and the extension members are written at the top level.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+len(standardMembers))
+	for key, value := range p.Extensions {
+		if !slices.Contains(standardMembers, key) {
+			members[key] = value
+		}
+	}
+
+	if p.Type != "" {
+		members["type"] = p.Type
+	}
+	if p.Title != "" {
+		members["title"] = p.Title
+	}
+	if p.Status != 0 {
+		members["status"] = p.Status
+	}
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return json.Marshal(members)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+//
+// As required by RFC 9457, the standard members that have a wrong type are ignored.
+// Other members are stored in Extensions.
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	*p = Problem{}
+	for key, raw := range members {
+		switch key {
+		case "type":
+			_ = json.Unmarshal(raw, &p.Type)
+		case "title":
+			_ = json.Unmarshal(raw, &p.Title)
+		case "status":
+			_ = json.Unmarshal(raw, &p.Status)
+		case "detail":
+			_ = json.Unmarshal(raw, &p.Detail)
+		case "instance":
+			_ = json.Unmarshal(raw, &p.Instance)
+		default:
+			var value any
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return err
+			}
+			if p.Extensions == nil {
+				p.Extensions = make(map[string]any)
+			}
+			p.Extensions[key] = value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package problem_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+	"github.com/Siroshun09/serrors/problem"
+)
+
+func TestNew(t *testing.T) {
+	err := problem.New(http.StatusNotFound, "user 1 is not found")
+	if err.Error() != "Not Found: user 1 is not found" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	stackTrace, ok := serrors.GetAttachedStackTrace(err)
+	if !ok {
+		t.Fatal("New() does not have a StackTrace")
+	}
+	if want := "github.com/Siroshun09/serrors/problem_test.TestNew"; stackTrace[0].Name != want {
+		t.Errorf("StackTrace[0] = %s, want %s", stackTrace[0].Name, want)
+	}
+
+	var p *problem.Problem
+	if !errors.As(err, &p) {
+		t.Fatal("errors.As(err, *Problem) = false, want true")
+	}
+	want := &problem.Problem{Title: "Not Found", Status: http.StatusNotFound, Detail: "user 1 is not found"}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("Problem = %+v, want %+v", p, want)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	base := errors.New("connection refused")
+
+	if err := problem.Wrap(base, nil); err != base {
+		t.Errorf("Wrap(err, nil) = %v, want %v", err, base)
+	}
+
+	err := problem.Wrap(base, &problem.Problem{Type: "https://example.com/probs/unavailable", Title: "Service Unavailable", Status: http.StatusServiceUnavailable})
+	if !errors.Is(err, base) {
+		t.Error("errors.Is(err, base) = false, want true")
+	}
+	if err.Error() != "Service Unavailable: connection refused" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	stackTrace, ok := serrors.GetAttachedStackTrace(err)
+	if !ok {
+		t.Fatal("Wrap() does not have a StackTrace")
+	}
+	if want := "github.com/Siroshun09/serrors/problem_test.TestWrap"; stackTrace[0].Name != want {
+		t.Errorf("StackTrace[0] = %s, want %s", stackTrace[0].Name, want)
+	}
+}
+
+func TestWrap_template(t *testing.T) {
+	template := &problem.Problem{Title: "Service Unavailable", Status: http.StatusServiceUnavailable}
+	base1 := errors.New("connection refused")
+	base2 := errors.New("timeout")
+
+	err1 := problem.Wrap(base1, template)
+	err2 := problem.Wrap(base2, template)
+	if template.Unwrap() != nil {
+		t.Errorf("template.Unwrap() = %v, want nil", template.Unwrap())
+	}
+	if !errors.Is(err1, base1) || errors.Is(err1, base2) {
+		t.Errorf("err1 = %v, want to wrap only %v", err1, base1)
+	}
+	if !errors.Is(err2, base2) || errors.Is(err2, base1) {
+		t.Errorf("err2 = %v, want to wrap only %v", err2, base2)
+	}
+}
+
+func TestProblem_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		problem *problem.Problem
+		want    string
+	}{
+		{
+			name:    "title and detail",
+			problem: &problem.Problem{Title: "Out of credit", Detail: "Your current balance is 30, but that costs 50."},
+			want:    "Out of credit: Your current balance is 30, but that costs 50.",
+		},
+		{
+			name:    "title only",
+			problem: &problem.Problem{Title: "Out of credit"},
+			want:    "Out of credit",
+		},
+		{
+			name:    "status only",
+			problem: &problem.Problem{Status: http.StatusForbidden},
+			want:    "Forbidden",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.problem.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProblem_StatusCode(t *testing.T) {
+	if got := (&problem.Problem{Status: http.StatusConflict}).StatusCode(); got != http.StatusConflict {
+		t.Errorf("StatusCode() = %d, want %d", got, http.StatusConflict)
+	}
+	if got := (&problem.Problem{}).StatusCode(); got != http.StatusInternalServerError {
+		t.Errorf("StatusCode() = %d, want %d", got, http.StatusInternalServerError)
+	}
+}
+
+func TestProblem_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		problem *problem.Problem
+		want    string
+	}{
+		{
+			name:    "empty",
+			problem: &problem.Problem{},
+			want:    `{}`,
+		},
+		{
+			name: "all members",
+			problem: &problem.Problem{
+				Type:       "https://example.com/probs/out-of-credit",
+				Title:      "You do not have enough credit.",
+				Status:     http.StatusForbidden,
+				Detail:     "Your current balance is 30, but that costs 50.",
+				Instance:   "/account/12345/msgs/abc",
+				Extensions: map[string]any{"balance": 30, "status": "ignored"},
+			},
+			want: `{"balance":30,"detail":"Your current balance is 30, but that costs 50.","instance":"/account/12345/msgs/abc","status":403,"title":"You do not have enough credit.","type":"https://example.com/probs/out-of-credit"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.problem)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProblem_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *problem.Problem
+		wantErr bool
+	}{
+		{
+			name: "all members",
+			data: `{"type":"https://example.com/probs/out-of-credit","title":"Out of credit","status":403,"detail":"detail","instance":"/account/12345","balance":30,"accounts":["/account/12345"]}`,
+			want: &problem.Problem{
+				Type:       "https://example.com/probs/out-of-credit",
+				Title:      "Out of credit",
+				Status:     http.StatusForbidden,
+				Detail:     "detail",
+				Instance:   "/account/12345",
+				Extensions: map[string]any{"balance": float64(30), "accounts": []any{"/account/12345"}},
+			},
+		},
+		{
+			name: "wrong types",
+			data: `{"title":1,"status":"403","detail":"detail"}`,
+			want: &problem.Problem{Detail: "detail"},
+		},
+		{
+			name:    "not an object",
+			data:    `[]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &problem.Problem{}
+			err := json.Unmarshal([]byte(tt.data), got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("json.Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("json.Unmarshal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
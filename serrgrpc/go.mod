@@ -0,0 +1,19 @@
+module github.com/Siroshun09/serrors/serrgrpc
+
+go 1.24.0
+
+require (
+	github.com/Siroshun09/serrors v1.5.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250804133106-a7a43d27e69b
+	google.golang.org/grpc v1.76.0
+)
+
+require (
+	golang.org/x/net v0.42.0 // indirect
+	golang.org/x/sys v0.34.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+	google.golang.org/protobuf v1.36.6 // indirect
+)
+
+// serrors v1.5.0 is not tagged yet, so it is built from the parent directory until then.
+replace github.com/Siroshun09/serrors v1.5.0 => ../
@@ -0,0 +1,91 @@
+package serrgrpc
+
+import (
+	"context"
+	"io"
+
+	"github.com/Siroshun09/serrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor that converts the errors returned by handlers into gRPC statuses by ToStatus.
+//
+// Panics in handlers are recovered by serrors.Catch, and returned as errors with codes.Internal.
+func UnaryServerInterceptor(opt Option) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var resp any
+		err := serrors.Catch(func() error {
+			var err error
+			resp, err = handler(ctx, req)
+			return err
+		})
+		if err != nil {
+			return nil, ToStatus(err, opt).Err()
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor returns a grpc.StreamServerInterceptor that converts the errors returned by handlers into gRPC statuses by ToStatus.
+//
+// Panics in handlers are recovered in the same way as UnaryServerInterceptor.
+func StreamServerInterceptor(opt Option) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := serrors.Catch(func() error {
+			return handler(srv, ss)
+		})
+		if err != nil {
+			return ToStatus(err, opt).Err()
+		}
+		return nil
+	}
+}
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor that rebuilds errors from gRPC statuses by FromStatus.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return fromError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor returns a grpc.StreamClientInterceptor that rebuilds errors from gRPC statuses by FromStatus.
+//
+// The errors returned by the methods of the grpc.ClientStream are also rebuilt, except for io.EOF.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, fromError(err)
+		}
+		return &clientStream{ClientStream: stream}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	return fromError(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	return fromError(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) CloseSend() error {
+	return fromError(s.ClientStream.CloseSend())
+}
+
+func fromError(err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return FromStatus(st)
+}
@@ -0,0 +1,176 @@
+package serrgrpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+	"github.com/Siroshun09/serrors/serrgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var errSentinel = errors.New("sentinel")
+
+func init() {
+	serrors.RegisterSentinel("serrgrpc_test.errSentinel", errSentinel)
+}
+
+// healthServer returns the error created by newErr from every method.
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	newErr func() error
+}
+
+func (s *healthServer) Check(context.Context, *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if err := s.newErr(); err != nil {
+		return nil, err
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func (s *healthServer) Watch(_ *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}); err != nil {
+		return err
+	}
+	return s.newErr()
+}
+
+func newClient(t *testing.T, opt serrgrpc.Option, newErr func() error) grpc_health_v1.HealthClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(serrgrpc.UnaryServerInterceptor(opt)),
+		grpc.StreamInterceptor(serrgrpc.StreamServerInterceptor(opt)),
+	)
+	grpc_health_v1.RegisterHealthServer(server, &healthServer{newErr: newErr})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(serrgrpc.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(serrgrpc.StreamClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func callUnary(t *testing.T, client grpc_health_v1.HealthClient) error {
+	_, err := client.Check(t.Context(), &grpc_health_v1.HealthCheckRequest{})
+	return err
+}
+
+func callStream(t *testing.T, client grpc_health_v1.HealthClient) error {
+	stream, err := client.Watch(t.Context(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	for {
+		if _, err := stream.Recv(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	serverErrs := make(chan error, 1)
+	newErr := func() error {
+		err := serrors.With(serrors.Errorf("wrap: %w", errSentinel), "key", "value")
+		serverErrs <- err
+		return err
+	}
+
+	calls := []struct {
+		name string
+		call func(*testing.T, grpc_health_v1.HealthClient) error
+	}{
+		{name: "unary", call: callUnary},
+		{name: "stream", call: callStream},
+	}
+	for _, c := range calls {
+		t.Run(c.name+" / Debug = true", func(t *testing.T) {
+			err := c.call(t, newClient(t, serrgrpc.Option{Debug: true}, newErr))
+			serverErr := <-serverErrs
+
+			if status.Code(err) != codes.Unknown {
+				t.Errorf("status.Code() = %v, want %v", status.Code(err), codes.Unknown)
+			}
+			if err.Error() != "wrap: sentinel" {
+				t.Errorf("Error() = %q, want %q", err.Error(), "wrap: sentinel")
+			}
+			if !errors.Is(err, errSentinel) {
+				t.Error("errors.Is(err, errSentinel) = false, want true")
+			}
+
+			stackTrace, ok := serrors.GetAttachedStackTrace(err)
+			if !ok {
+				t.Fatal("GetAttachedStackTrace() = false, want true")
+			}
+			if want := serrors.GetStackTrace(serverErr); !reflect.DeepEqual(stackTrace, want) {
+				t.Errorf("GetAttachedStackTrace() = %v, want the remote StackTrace %v", stackTrace, want)
+			}
+			if attrs := serrors.Attributes(err); len(attrs) != 1 || attrs[0].Key != "key" {
+				t.Errorf("Attributes() = %v, want key=value", attrs)
+			}
+		})
+
+		t.Run(c.name+" / Debug = false", func(t *testing.T) {
+			err := c.call(t, newClient(t, serrgrpc.Option{}, newErr))
+			<-serverErrs
+
+			if status.Code(err) != codes.Unknown || err.Error() != "rpc error: code = Unknown desc = wrap: sentinel" {
+				t.Errorf("err = %v, want the status error", err)
+			}
+			if _, ok := serrors.GetAttachedStackTrace(err); ok {
+				t.Error("GetAttachedStackTrace() = true, want false")
+			}
+		})
+
+		t.Run(c.name+" / panic", func(t *testing.T) {
+			err := c.call(t, newClient(t, serrgrpc.Option{Debug: true}, func() error {
+				panic("test")
+			}))
+
+			if status.Code(err) != codes.Internal {
+				t.Errorf("status.Code() = %v, want %v", status.Code(err), codes.Internal)
+			}
+			if err.Error() != "panic: test" {
+				t.Errorf("Error() = %q, want %q", err.Error(), "panic: test")
+			}
+		})
+
+		t.Run(c.name+" / no error", func(t *testing.T) {
+			if err := c.call(t, newClient(t, serrgrpc.Option{Debug: true}, func() error { return nil })); err != nil {
+				t.Errorf("err = %v, want nil", err)
+			}
+		})
+
+		t.Run(c.name+" / status error", func(t *testing.T) {
+			err := c.call(t, newClient(t, serrgrpc.Option{}, func() error {
+				return status.Error(codes.NotFound, "not found")
+			}))
+
+			if st, _ := status.FromError(err); st.Code() != codes.NotFound || st.Message() != "not found" {
+				t.Errorf("status = %v, want NotFound: not found", st)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// Package serrgrpc provides gRPC interceptors that carry errors created by serrors across gRPC calls.
+package serrgrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Siroshun09/serrors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Option is the option for the server interceptors and ToStatus.
+type Option struct {
+	// Debug is whether to add the error chain and its StackTraces to the status details.
+	//
+	// The details contain the messages, type names, StackTraces and attributes of all errors in the chain.
+	// This should not be enabled for untrusted clients.
+	Debug bool
+	// Code returns the gRPC status code for the error.
+	//
+	// If nil, Code (the function of this package) is used.
+	Code func(err error) codes.Code
+}
+
+// ToStatus converts err into a gRPC status.
+//
+// The status has the code returned by Option.Code and the message of err.
+// If the error chain has a gRPC status (such as the errors created by status.Error), the status is based on it instead,
+// so its message and details (such as errdetails.BadRequest) are kept, except for errdetails.DebugInfo.
+// If Option.Debug is true, the status has an errdetails.DebugInfo detail:
+// its StackEntries are the frames of the first StackTrace of err (see serrors.GetStackTraceEntries),
+// followed by "... N more frames" if the StackTrace is truncated,
+// and its Detail is the serrors.Envelope of err encoded as JSON, which is used by FromStatus to rebuild the error.
+//
+// If err is nil, this function returns nil.
+func ToStatus(err error, opt Option) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	code := Code
+	if opt.Code != nil {
+		code = opt.Code
+	}
+
+	c := code(err)
+	if c == codes.OK {
+		// the status of codes.OK is not an error, so err would be lost
+		c = codes.Unknown
+	}
+
+	st := newStatus(err, c)
+	if !opt.Debug {
+		return st
+	}
+
+	env, marshalErr := json.Marshal(serrors.Encode(err))
+	if marshalErr != nil {
+		return st
+	}
+
+	debugInfo := &errdetails.DebugInfo{Detail: string(env)}
+	for entry := range serrors.GetStackTraceEntries(err) {
+		for _, funcInfo := range entry.StackTrace {
+			debugInfo.StackEntries = append(debugInfo.StackEntries, funcInfo.String())
+		}
+		if entry.Omitted != 0 {
+			debugInfo.StackEntries = append(debugInfo.StackEntries, serrors.FormatOmittedFrames(entry.Omitted))
+		}
+		break // the other StackTraces are in Detail
+	}
+
+	if withDetails, detailErr := st.WithDetails(debugInfo); detailErr == nil {
+		return withDetails
+	}
+	return st
+}
+
+func newStatus(err error, c codes.Code) *status.Status {
+	var grpcStatus interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcStatus) || grpcStatus.GRPCStatus() == nil {
+		return status.New(c, err.Error())
+	}
+
+	p := grpcStatus.GRPCStatus().Proto()
+	p.Code = int32(c)
+
+	// the DebugInfo of the original status is replaced by the one of err, or must not be exposed if Option.Debug is false
+	details := p.Details[:0]
+	for _, detail := range p.Details {
+		if !detail.MessageIs(&errdetails.DebugInfo{}) {
+			details = append(details, detail)
+		}
+	}
+	p.Details = details
+
+	return status.FromProto(p)
+}
+
+// FromStatus rebuilds an error from the gRPC status.
+//
+// If the status has the details added by ToStatus, the returned error has the remote error chain decoded by serrors.Decode,
+// so serrors.GetAttachedStackTrace returns the remote StackTrace and errors.Is matches the sentinels registered by serrors.RegisterSentinel.
+// Otherwise, this function returns st.Err().
+//
+// In both cases, status.FromError and status.Code return st.
+// If st is nil or its code is codes.OK, this function returns nil.
+func FromStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	for _, detail := range st.Details() {
+		debugInfo, ok := detail.(*errdetails.DebugInfo)
+		if !ok {
+			continue
+		}
+
+		var env serrors.Envelope
+		if err := json.Unmarshal([]byte(debugInfo.GetDetail()), &env); err != nil {
+			continue
+		}
+		return &remoteError{st: st, err: serrors.Decode(&env)}
+	}
+
+	return st.Err()
+}
+
+// remoteError is an error rebuilt from the status details.
+type remoteError struct {
+	st  *status.Status
+	err error
+}
+
+func (e *remoteError) Error() string {
+	return e.err.Error()
+}
+
+func (e *remoteError) Unwrap() error {
+	return e.err
+}
+
+// GRPCStatus returns the original status for status.FromError.
+func (e *remoteError) GRPCStatus() *status.Status {
+	return e.st
+}
+
+// Format implements fmt.Formatter in the same way as the rebuilt error.
+func (e *remoteError) Format(s fmt.State, verb rune) {
+	_, _ = fmt.Fprintf(s, fmt.FormatString(s, verb), e.err)
+}
+
+// Code returns the gRPC status code for err.
+//
+// The code is determined in the following order:
+//
+//   - the code of the gRPC status in the error chain (see status.FromError)
+//   - codes.Canceled and codes.DeadlineExceeded for context.Canceled and context.DeadlineExceeded
+//   - codes.Internal for panics recovered by serrors (*serrors.PanicError)
+//   - the code mapped from the HTTP status code of the first error that has a StatusCode() int method
+//   - codes.Unknown
+func Code(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+
+	var grpcStatus interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcStatus) {
+		return grpcStatus.GRPCStatus().Code()
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	}
+
+	var panicErr *serrors.PanicError
+	if errors.As(err, &panicErr) {
+		return codes.Internal
+	}
+
+	var coder interface{ StatusCode() int }
+	if errors.As(err, &coder) {
+		return httpStatusToCode(coder.StatusCode())
+	}
+
+	return codes.Unknown
+}
+
+// httpStatusToCode maps the HTTP status code in the reverse of the mapping from google.rpc.Code to HTTP status codes.
+func httpStatusToCode(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499: // Client Closed Request
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+
+	switch {
+	case 400 <= code && code < 500:
+		return codes.FailedPrecondition
+	case 500 <= code && code < 600:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
@@ -0,0 +1,209 @@
+package serrgrpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+	"github.com/Siroshun09/serrors/serrgrpc"
+	"github.com/Siroshun09/serrors/serrorstest"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type statusCodeError int
+
+func (e statusCodeError) Error() string   { return http.StatusText(int(e)) }
+func (e statusCodeError) StatusCode() int { return int(e) }
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{name: "nil", err: nil, want: codes.OK},
+		{name: "unknown", err: errors.New("test"), want: codes.Unknown},
+		{name: "status", err: fmt.Errorf("wrap: %w", status.Error(codes.NotFound, "test")), want: codes.NotFound},
+		{name: "canceled", err: serrors.WithStackTrace(context.Canceled), want: codes.Canceled},
+		{name: "deadline exceeded", err: fmt.Errorf("wrap: %w", context.DeadlineExceeded), want: codes.DeadlineExceeded},
+		{name: "panic", err: &serrors.PanicError{Value: "test"}, want: codes.Internal},
+		{name: "HTTP 404", err: statusCodeError(http.StatusNotFound), want: codes.NotFound},
+		{name: "HTTP 418", err: statusCodeError(http.StatusTeapot), want: codes.FailedPrecondition},
+		{name: "HTTP 503", err: statusCodeError(http.StatusServiceUnavailable), want: codes.Unavailable},
+		{name: "HTTP 502", err: statusCodeError(http.StatusBadGateway), want: codes.Internal},
+		{name: "HTTP 200", err: statusCodeError(http.StatusOK), want: codes.Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serrgrpc.Code(tt.err); got != tt.want {
+				t.Errorf("Code() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	stackTrace := serrors.StackTrace{
+		{Name: "example.com/app.handle", File: "/src/app/handler.go", Line: 10},
+		{Name: "example.com/app.main", File: "/src/app/main.go", Line: 20},
+	}
+	err := serrorstest.WithStackTrace(errors.New("test"), stackTrace)
+
+	if st := serrgrpc.ToStatus(nil, serrgrpc.Option{}); st != nil {
+		t.Errorf("ToStatus(nil) = %v, want nil", st)
+	}
+
+	t.Run("Debug = false", func(t *testing.T) {
+		st := serrgrpc.ToStatus(err, serrgrpc.Option{})
+		if st.Code() != codes.Unknown || st.Message() != "test" || len(st.Details()) != 0 {
+			t.Errorf("ToStatus() = %v, want Unknown: test without details", st)
+		}
+	})
+
+	t.Run("Debug = true", func(t *testing.T) {
+		st := serrgrpc.ToStatus(err, serrgrpc.Option{Debug: true})
+		if len(st.Details()) != 1 {
+			t.Fatalf("Details() = %v, want 1 detail", st.Details())
+		}
+
+		debugInfo, ok := st.Details()[0].(*errdetails.DebugInfo)
+		if !ok {
+			t.Fatalf("Details()[0] = %T, want *errdetails.DebugInfo", st.Details()[0])
+		}
+		want := []string{"example.com/app.handle (/src/app/handler.go:10)", "example.com/app.main (/src/app/main.go:20)"}
+		if !reflect.DeepEqual(debugInfo.GetStackEntries(), want) {
+			t.Errorf("StackEntries = %v, want %v", debugInfo.GetStackEntries(), want)
+		}
+	})
+
+	t.Run("Debug = true, truncated", func(t *testing.T) {
+		truncated := serrors.Decode(&serrors.Envelope{
+			Message:    "test",
+			Kind:       serrors.EnvelopeKindStackTrace,
+			StackTrace: stackTrace[:1],
+			Omitted:    1,
+			Causes:     []*serrors.Envelope{{Message: "test"}},
+		})
+		st := serrgrpc.ToStatus(truncated, serrgrpc.Option{Debug: true})
+
+		debugInfo, ok := st.Details()[0].(*errdetails.DebugInfo)
+		if !ok {
+			t.Fatalf("Details()[0] = %T, want *errdetails.DebugInfo", st.Details()[0])
+		}
+		want := []string{"example.com/app.handle (/src/app/handler.go:10)", "... 1 more frame"}
+		if !reflect.DeepEqual(debugInfo.GetStackEntries(), want) {
+			t.Errorf("StackEntries = %v, want %v", debugInfo.GetStackEntries(), want)
+		}
+	})
+
+	t.Run("Code", func(t *testing.T) {
+		st := serrgrpc.ToStatus(err, serrgrpc.Option{Code: func(error) codes.Code { return codes.Aborted }})
+		if st.Code() != codes.Aborted {
+			t.Errorf("Code() = %v, want %v", st.Code(), codes.Aborted)
+		}
+	})
+
+	t.Run("status error", func(t *testing.T) {
+		base, detailErr := status.New(codes.InvalidArgument, "invalid name").WithDetails(
+			&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "name"}}},
+			&errdetails.DebugInfo{Detail: "upstream"},
+		)
+		if detailErr != nil {
+			t.Fatal(detailErr)
+		}
+
+		st := serrgrpc.ToStatus(serrors.WithStackTrace(fmt.Errorf("wrap: %w", base.Err())), serrgrpc.Option{Debug: true})
+		if st.Code() != codes.InvalidArgument || st.Message() != "invalid name" {
+			t.Errorf("ToStatus() = %v, want InvalidArgument: invalid name", st)
+		}
+
+		details := st.Details()
+		if len(details) != 2 {
+			t.Fatalf("Details() = %v, want BadRequest and DebugInfo", details)
+		}
+		if _, ok := details[0].(*errdetails.BadRequest); !ok {
+			t.Errorf("Details()[0] = %T, want *errdetails.BadRequest", details[0])
+		}
+		if debugInfo, ok := details[1].(*errdetails.DebugInfo); !ok || debugInfo.GetDetail() == "upstream" {
+			t.Errorf("Details()[1] = %v, want the DebugInfo of the error", details[1])
+		}
+	})
+
+	t.Run("Code returns OK", func(t *testing.T) {
+		st := serrgrpc.ToStatus(err, serrgrpc.Option{Code: func(error) codes.Code { return codes.OK }})
+		if st.Code() != codes.Unknown {
+			t.Errorf("Code() = %v, want %v", st.Code(), codes.Unknown)
+		}
+	})
+}
+
+func TestFromStatus(t *testing.T) {
+	stackTrace := serrors.StackTrace{{Name: "example.com/app.handle", File: "/src/app/handler.go", Line: 10}}
+	err := serrorstest.WithStackTrace(errors.New("test"), stackTrace)
+
+	tests := []struct {
+		name           string
+		st             *status.Status
+		wantNil        bool
+		wantStackTrace serrors.StackTrace
+	}{
+		{
+			name:    "nil",
+			st:      nil,
+			wantNil: true,
+		},
+		{
+			name:    "OK",
+			st:      status.New(codes.OK, ""),
+			wantNil: true,
+		},
+		{
+			name: "without details",
+			st:   status.New(codes.NotFound, "test"),
+		},
+		{
+			name:           "with details",
+			st:             serrgrpc.ToStatus(err, serrgrpc.Option{Debug: true}),
+			wantStackTrace: stackTrace,
+		},
+		{
+			name: "invalid details",
+			st: func() *status.Status {
+				st, _ := status.New(codes.Internal, "test").WithDetails(&errdetails.DebugInfo{Detail: "not json"})
+				return st
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := serrgrpc.FromStatus(tt.st)
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("FromStatus() = %v, want nil", got)
+				}
+				return
+			}
+
+			if gotStatus, _ := status.FromError(got); !reflect.DeepEqual(gotStatus, tt.st) {
+				t.Errorf("status.FromError() = %v, want %v", gotStatus, tt.st)
+			}
+			stackTrace, _ := serrors.GetAttachedStackTrace(got)
+			if !reflect.DeepEqual(stackTrace, tt.wantStackTrace) {
+				t.Errorf("GetAttachedStackTrace() = %v, want %v", stackTrace, tt.wantStackTrace)
+			}
+		})
+	}
+
+	t.Run("format", func(t *testing.T) {
+		got := serrgrpc.FromStatus(serrgrpc.ToStatus(err, serrgrpc.Option{Debug: true}))
+		if want := fmt.Sprintf("%+v", err); fmt.Sprintf("%+v", got) != want {
+			t.Errorf("%%+v = %q, want %q", fmt.Sprintf("%+v", got), want)
+		}
+	})
+}
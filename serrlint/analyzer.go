@@ -0,0 +1,411 @@
+// Package serrlint provides an analyzer that reports errors losing or duplicating the StackTraces of serrors.
+//
+// The analyzer reports the following:
+//
+//   - errors.New and fmt.Errorf returned from exported functions in packages that use serrors
+//   - serrors.WithStackTrace called on the result of serrors.New or serrors.Errorf, which already has a StackTrace
+//   - errors formatted with %v or %s by fmt.Errorf, which drops the error chain and its StackTrace (use %w instead)
+//   - errors rebuilt from err.Error(), which drops the error chain and its StackTrace
+//
+// Each diagnostic has a SuggestedFix that rewrites the code to serrors.
+//
+// The analyzer can be run by go vet (go vet -vettool=$(which serrlint) ./...) or by the serrlint command directly.
+package serrlint
+
+import (
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// SerrorsPath is the import path of serrors.
+const SerrorsPath = "github.com/Siroshun09/serrors"
+
+// Analyzer reports errors losing or duplicating the StackTraces of serrors.
+var Analyzer = &analysis.Analyzer{
+	Name: "serrlint",
+	Doc:  "report errors that lose or duplicate the stack traces of github.com/Siroshun09/serrors",
+	URL:  "https://pkg.go.dev/github.com/Siroshun09/serrors/serrlint",
+	Run:  run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	c := &checker{
+		pass:         pass,
+		usesSerrors:  importsSerrors(pass.Pkg),
+		rebuiltCalls: map[*ast.CallExpr]bool{},
+	}
+
+	for _, file := range pass.Files {
+		c.file = file
+		// checkCall runs first, so checkReturns can skip the calls that are already rewritten as rebuilt errors.
+		ast.Inspect(file, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				c.checkCall(call)
+			}
+			return true
+		})
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok {
+				c.checkReturns(fn)
+			}
+		}
+	}
+	return nil, nil
+}
+
+type checker struct {
+	pass        *analysis.Pass
+	file        *ast.File
+	usesSerrors bool
+	// rebuiltCalls is the set of calls that are reported as errors rebuilt from err.Error().
+	rebuiltCalls map[*ast.CallExpr]bool
+}
+
+func importsSerrors(pkg *types.Package) bool {
+	for _, imported := range pkg.Imports() {
+		if imported.Path() == SerrorsPath {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *checker) calleeName(call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return ""
+	}
+	if sig, ok := fn.Type().(*types.Signature); !ok || sig.Recv() != nil {
+		return ""
+	}
+	return fn.Pkg().Path() + "." + fn.Name()
+}
+
+func (c *checker) checkCall(call *ast.CallExpr) {
+	switch c.calleeName(call) {
+	case "errors.New", SerrorsPath + ".New":
+		c.checkRebuiltNew(call)
+	case "fmt.Errorf", SerrorsPath + ".Errorf":
+		c.checkErrorf(call)
+	case SerrorsPath + ".WithStackTrace":
+		c.checkRedundantWithStackTrace(call)
+	}
+}
+
+// checkRebuiltNew reports errors.New(err.Error()) and serrors.New(err.Error()).
+func (c *checker) checkRebuiltNew(call *ast.CallExpr) {
+	if len(call.Args) != 1 {
+		return
+	}
+	if err := c.errorMethodReceiver(call.Args[0]); err != nil {
+		c.reportRebuilt(call, err)
+	}
+}
+
+// reportRebuilt reports call that rebuilds the error err from err.Error(), and suggests serrors.WithStackTrace(err).
+func (c *checker) reportRebuilt(call *ast.CallExpr, err ast.Expr) {
+	c.rebuiltCalls[call] = true
+
+	edits := []analysis.TextEdit{
+		c.replace(call, c.serrorsName()+".WithStackTrace("+c.text(err)+")"),
+	}
+	edits = append(edits, c.importEdits(call)...)
+
+	c.pass.Report(analysis.Diagnostic{
+		Pos:     call.Pos(),
+		End:     call.End(),
+		Message: "error rebuilt from " + c.text(err) + ".Error() loses its chain and stack trace; use serrors.WithStackTrace",
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message:   "Replace with serrors.WithStackTrace",
+			TextEdits: edits,
+		}},
+	})
+}
+
+// checkErrorf reports errors formatted with %v or %s, and errors rebuilt from err.Error() by fmt.Errorf or serrors.Errorf.
+func (c *checker) checkErrorf(call *ast.CallExpr) {
+	if len(call.Args) == 0 || call.Ellipsis.IsValid() {
+		return
+	}
+
+	if len(call.Args) == 1 {
+		// fmt.Errorf(err.Error())
+		if err := c.errorMethodReceiver(call.Args[0]); err != nil {
+			c.reportRebuilt(call, err)
+		}
+		return
+	}
+
+	lit, ok := ast.Unparen(call.Args[0]).(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return
+	}
+	verbs, ok := parseVerbs(lit.Value)
+	if !ok {
+		return
+	}
+
+	args := call.Args[1:]
+	for i, verb := range verbs {
+		if len(args) <= i {
+			return
+		}
+		if !verb.plain() {
+			continue
+		}
+
+		arg := args[i]
+		verbPos := lit.Pos() + token.Pos(verb.offset)
+		verbEdit := analysis.TextEdit{Pos: verbPos, End: verbPos + 1, NewText: []byte("w")}
+
+		if err := c.errorMethodReceiver(arg); err != nil {
+			c.pass.Report(analysis.Diagnostic{
+				Pos:     arg.Pos(),
+				End:     arg.End(),
+				Message: "error formatted from " + c.text(err) + ".Error() loses its chain and stack trace; use %w with " + c.text(err),
+				SuggestedFixes: []analysis.SuggestedFix{{
+					Message:   "Replace with %w",
+					TextEdits: []analysis.TextEdit{verbEdit, c.replace(arg, c.text(err))},
+				}},
+			})
+			continue
+		}
+
+		if c.isError(arg) {
+			c.pass.Report(analysis.Diagnostic{
+				Pos:     arg.Pos(),
+				End:     arg.End(),
+				Message: "error formatted with %" + string(verb.verb) + " loses its chain and stack trace; use %w",
+				SuggestedFixes: []analysis.SuggestedFix{{
+					Message:   "Replace with %w",
+					TextEdits: []analysis.TextEdit{verbEdit},
+				}},
+			})
+		}
+	}
+}
+
+// checkRedundantWithStackTrace reports serrors.WithStackTrace(serrors.New(...)) and serrors.WithStackTrace(serrors.Errorf(...)).
+func (c *checker) checkRedundantWithStackTrace(call *ast.CallExpr) {
+	if len(call.Args) != 1 {
+		return
+	}
+	inner, ok := ast.Unparen(call.Args[0]).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+
+	switch name := c.calleeName(inner); name {
+	case SerrorsPath + ".New", SerrorsPath + ".Errorf", SerrorsPath + ".WithStackTrace":
+		short := "serrors." + name[len(SerrorsPath)+1:]
+		c.pass.Report(analysis.Diagnostic{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			Message: "redundant serrors.WithStackTrace: the result of " + short + " already has a stack trace",
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message:   "Remove serrors.WithStackTrace",
+				TextEdits: []analysis.TextEdit{c.replace(call, c.text(inner))},
+			}},
+		})
+	}
+}
+
+// checkReturns reports errors.New and fmt.Errorf returned from the exported function fn.
+func (c *checker) checkReturns(fn *ast.FuncDecl) {
+	if !c.usesSerrors || !fn.Name.IsExported() || fn.Body == nil {
+		return
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			// returns in function literals do not return from fn
+			return false
+		case *ast.ReturnStmt:
+			for _, result := range n.Results {
+				call, ok := ast.Unparen(result).(*ast.CallExpr)
+				if !ok || c.rebuiltCalls[call] {
+					continue
+				}
+				switch name := c.calleeName(call); name {
+				case "errors.New", "fmt.Errorf":
+					c.reportReturn(fn, call, name)
+				}
+			}
+		}
+		return true
+	})
+}
+
+func (c *checker) reportReturn(fn *ast.FuncDecl, call *ast.CallExpr, name string) {
+	replacement := "New"
+	if name == "fmt.Errorf" {
+		replacement = "Errorf"
+	}
+
+	edits := []analysis.TextEdit{c.replace(call.Fun, c.serrorsName()+"."+replacement)}
+	edits = append(edits, c.importEdits(call)...)
+
+	c.pass.Report(analysis.Diagnostic{
+		Pos:     call.Pos(),
+		End:     call.End(),
+		Message: "exported function " + fn.Name.Name + " returns an error without a stack trace; use serrors." + replacement,
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message:   "Replace with serrors." + replacement,
+			TextEdits: edits,
+		}},
+	})
+}
+
+// errorMethodReceiver returns x if expr is x.Error() and x is an error, otherwise nil.
+func (c *checker) errorMethodReceiver(expr ast.Expr) ast.Expr {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Error" || !c.isError(sel.X) {
+		return nil
+	}
+	return sel.X
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+func (c *checker) isError(expr ast.Expr) bool {
+	t := c.pass.TypesInfo.TypeOf(expr)
+	return t != nil && types.Implements(t, errorType)
+}
+
+// text returns the source code of node.
+func (c *checker) text(node ast.Node) string {
+	start := c.pass.Fset.Position(node.Pos()).Offset
+	end := c.pass.Fset.Position(node.End()).Offset
+	content, err := c.pass.ReadFile(c.pass.Fset.File(node.Pos()).Name())
+	if err == nil && end <= len(content) {
+		return string(content[start:end])
+	}
+
+	var b strings.Builder
+	_ = format.Node(&b, c.pass.Fset, node)
+	return b.String()
+}
+
+func (c *checker) replace(node ast.Node, text string) analysis.TextEdit {
+	return analysis.TextEdit{Pos: node.Pos(), End: node.End(), NewText: []byte(text)}
+}
+
+// serrorsName returns the name of serrors imported in the current file.
+func (c *checker) serrorsName() string {
+	if spec := c.importSpec(SerrorsPath); spec != nil && spec.Name != nil {
+		return spec.Name.Name
+	}
+	return "serrors"
+}
+
+func (c *checker) importSpec(path string) *ast.ImportSpec {
+	for _, spec := range c.file.Imports {
+		if p, err := strconv.Unquote(spec.Path.Value); err == nil && p == path {
+			return spec
+		}
+	}
+	return nil
+}
+
+// importEdits returns the edits that import serrors if the current file does not import it,
+// and remove the import of the package used by call.Fun if call is its only use.
+func (c *checker) importEdits(call *ast.CallExpr) []analysis.TextEdit {
+	remove, removeDecl, removeOK := c.removeUnusedImport(call)
+	if c.importSpec(SerrorsPath) != nil {
+		if removeOK {
+			return []analysis.TextEdit{remove}
+		}
+		return nil
+	}
+
+	if removeOK && !removeDecl.Lparen.IsValid() {
+		// replace the import declaration instead of removing it, so that the edits do not overlap
+		return []analysis.TextEdit{{Pos: removeDecl.Pos(), End: removeDecl.End(), NewText: []byte("import " + strconv.Quote(SerrorsPath))}}
+	}
+
+	edits := []analysis.TextEdit{c.addImport(SerrorsPath)}
+	if removeOK {
+		edits = append(edits, remove)
+	}
+	return edits
+}
+
+func (c *checker) addImport(path string) analysis.TextEdit {
+	for _, decl := range c.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Lparen.IsValid() {
+			return analysis.TextEdit{Pos: gen.Rparen, End: gen.Rparen, NewText: []byte("\t" + strconv.Quote(path) + "\n")}
+		}
+		spec := gen.Specs[0]
+		return analysis.TextEdit{
+			Pos:     gen.Pos(),
+			End:     gen.End(),
+			NewText: []byte("import (\n\t" + c.text(spec) + "\n\t" + strconv.Quote(path) + "\n)"),
+		}
+	}
+	return analysis.TextEdit{Pos: c.file.Name.End(), End: c.file.Name.End(), NewText: []byte("\n\nimport " + strconv.Quote(path))}
+}
+
+// removeUnusedImport returns the edit that removes the import of the package used by call.Fun and the declaration that contains it,
+// if the package is used only by call.
+func (c *checker) removeUnusedImport(call *ast.CallExpr) (analysis.TextEdit, *ast.GenDecl, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return analysis.TextEdit{}, nil, false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return analysis.TextEdit{}, nil, false
+	}
+	pkgName, ok := c.pass.TypesInfo.Uses[ident].(*types.PkgName)
+	if !ok || pkgName.Imported().Path() == SerrorsPath {
+		return analysis.TextEdit{}, nil, false
+	}
+
+	uses := 0
+	ast.Inspect(c.file, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && c.pass.TypesInfo.Uses[id] == pkgName {
+			uses++
+		}
+		return true
+	})
+	if uses != 1 {
+		return analysis.TextEdit{}, nil, false
+	}
+
+	for _, decl := range c.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if spec.(*ast.ImportSpec).Path.Value != strconv.Quote(pkgName.Imported().Path()) {
+				continue
+			}
+			if !gen.Lparen.IsValid() {
+				return analysis.TextEdit{Pos: gen.Pos(), End: gen.End()}, gen, true
+			}
+			// remove the whole line, including the indentation and the newline
+			tokFile := c.pass.Fset.File(spec.Pos())
+			start := tokFile.LineStart(tokFile.Line(spec.Pos()))
+			end := tokFile.LineStart(tokFile.Line(spec.End()) + 1)
+			return analysis.TextEdit{Pos: start, End: end}, gen, true
+		}
+	}
+	return analysis.TextEdit{}, nil, false
+}
@@ -0,0 +1,12 @@
+package serrlint_test
+
+import (
+	"testing"
+
+	"github.com/Siroshun09/serrors/serrlint"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), serrlint.Analyzer, "a", "b", "c")
+}
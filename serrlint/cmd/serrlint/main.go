@@ -0,0 +1,16 @@
+// Command serrlint reports errors that lose or duplicate the stack traces of github.com/Siroshun09/serrors.
+//
+// Usage:
+//
+//	serrlint [-fix] ./...
+//	go vet -vettool=$(which serrlint) ./...
+package main
+
+import (
+	"github.com/Siroshun09/serrors/serrlint"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(serrlint.Analyzer)
+}
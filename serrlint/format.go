@@ -0,0 +1,52 @@
+package serrlint
+
+import "strings"
+
+// formatVerb is a verb in a format string.
+type formatVerb struct {
+	verb rune
+	// offset is the offset of the verb in the string literal, including the quote.
+	offset int
+	// flags contains the flags, width and precision of the verb.
+	flags string
+}
+
+// plain reports whether the verb is %v or %s without flags, width and precision.
+func (v formatVerb) plain() bool {
+	return v.flags == "" && (v.verb == 'v' || v.verb == 's')
+}
+
+// parseVerbs parses the verbs in the string literal lit, and reports whether lit can be edited verb by verb.
+//
+// Literals that contain argument indexes, * widths or escaped percent signs are not supported.
+func parseVerbs(lit string) ([]formatVerb, bool) {
+	if strings.Contains(lit, `\x25`) || strings.Contains(lit, `\045`) || strings.Contains(lit, `\u0025`) || strings.Contains(lit, `\U00000025`) {
+		return nil, false
+	}
+
+	var verbs []formatVerb
+	for i := 1; i < len(lit)-1; i++ {
+		if lit[i] != '%' {
+			continue
+		}
+		i++
+		start := i
+		for i < len(lit)-1 && strings.IndexByte("+-# 0123456789.", lit[i]) >= 0 {
+			i++
+		}
+		if len(lit)-1 <= i {
+			return nil, false
+		}
+		switch lit[i] {
+		case '%':
+			if start != i {
+				return nil, false
+			}
+			continue
+		case '[', '*':
+			return nil, false
+		}
+		verbs = append(verbs, formatVerb{verb: rune(lit[i]), offset: i, flags: lit[start:i]})
+	}
+	return verbs, true
+}
@@ -0,0 +1,37 @@
+package serrlint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVerbs(t *testing.T) {
+	tests := []struct {
+		name   string
+		lit    string
+		want   []formatVerb
+		wantOK bool
+	}{
+		{name: "no verbs", lit: `"test"`, wantOK: true},
+		{
+			name:   "verbs",
+			lit:    `"%d: %v %+v %s"`,
+			want:   []formatVerb{{verb: 'd', offset: 2}, {verb: 'v', offset: 6}, {verb: 'v', offset: 10, flags: "+"}, {verb: 's', offset: 13}},
+			wantOK: true,
+		},
+		{name: "percent", lit: `"100%% %w"`, want: []formatVerb{{verb: 'w', offset: 8}}, wantOK: true},
+		{name: "width and precision", lit: "`%-8.2f`", want: []formatVerb{{verb: 'f', offset: 6, flags: "-8.2"}}, wantOK: true},
+		{name: "argument index", lit: `"%[1]v"`, wantOK: false},
+		{name: "star", lit: `"%*d"`, wantOK: false},
+		{name: "escaped percent", lit: `"\x25v"`, wantOK: false},
+		{name: "trailing percent", lit: `"test %"`, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseVerbs(tt.lit)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseVerbs() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Siroshun09/serrors"
+)
+
+var ErrNotFound = errors.New("not found")
+
+func Exported() error {
+	return errors.New("test") // want `exported function Exported returns an error without a stack trace; use serrors.New`
+}
+
+func ExportedErrorf(id int) (string, error) {
+	return "", fmt.Errorf("id %d: %w", id, ErrNotFound) // want `exported function ExportedErrorf returns an error without a stack trace; use serrors.Errorf`
+}
+
+func ExportedClosure() func() error {
+	return func() error {
+		return errors.New("test")
+	}
+}
+
+func unexported() error {
+	return errors.New("test")
+}
+
+func ExportedSerrors() error {
+	return serrors.New("test")
+}
+
+func Redundant() error {
+	return serrors.WithStackTrace(serrors.New("test")) // want `redundant serrors.WithStackTrace: the result of serrors.New already has a stack trace`
+}
+
+func RedundantErrorf(err error) error {
+	return serrors.WithStackTrace(serrors.Errorf("wrap: %w", err)) // want `redundant serrors.WithStackTrace: the result of serrors.Errorf already has a stack trace`
+}
+
+func WithStackTrace(err error) error {
+	return serrors.WithStackTrace(err)
+}
+
+func formatted(err error) error {
+	return serrors.Errorf("id %d: %v, %+v, %s", 1, err, err, err) // want `error formatted with %v loses its chain and stack trace; use %w` `error formatted with %s loses its chain and stack trace; use %w`
+}
+
+func formattedString(name string) error {
+	return serrors.Errorf("name %s", name)
+}
+
+func rebuilt(err error) error {
+	return serrors.New(err.Error()) // want `error rebuilt from err.Error\(\) loses its chain and stack trace; use serrors.WithStackTrace`
+}
+
+func rebuiltErrorf(err error) error {
+	return serrors.Errorf("wrap: %s", err.Error()) // want `error formatted from err.Error\(\) loses its chain and stack trace; use %w with err`
+}
+
+type T struct{}
+
+func (T) Method() error {
+	return errors.New("test") // want `exported function Method returns an error without a stack trace; use serrors.New`
+}
+
+func raw(err error) error {
+	return serrors.Errorf(`raw: %v`, err) // want `error formatted with %v loses its chain and stack trace; use %w`
+}
@@ -0,0 +1,12 @@
+// Package b does not use serrors.
+package b
+
+import "errors"
+
+func Exported() error {
+	return errors.New("test")
+}
+
+func Rebuilt(err error) error {
+	return errors.New(err.Error()) // want `error rebuilt from err.Error\(\) loses its chain and stack trace; use serrors.WithStackTrace`
+}
@@ -0,0 +1,5 @@
+package c
+
+import "github.com/Siroshun09/serrors"
+
+var errSerrors = serrors.New("test")
@@ -0,0 +1,13 @@
+package c
+
+import (
+	"fmt"
+	"io"
+)
+
+func Errorf(err error) error {
+	if err == io.EOF {
+		return nil
+	}
+	return fmt.Errorf(err.Error()) // want `error rebuilt from err.Error\(\) loses its chain and stack trace; use serrors.WithStackTrace`
+}
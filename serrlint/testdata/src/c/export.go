@@ -0,0 +1,7 @@
+package c
+
+import "errors"
+
+func Exported() error {
+	return errors.New("test") // want `exported function Exported returns an error without a stack trace; use serrors.New`
+}
@@ -0,0 +1,8 @@
+// Package serrors is a stub of github.com/Siroshun09/serrors for tests.
+package serrors
+
+func New(msg string) error { return nil }
+
+func Errorf(format string, args ...any) error { return nil }
+
+func WithStackTrace(err error) error { return err }
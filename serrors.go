@@ -4,16 +4,35 @@ import (
 	"errors"
 	"fmt"
 	"iter"
+	"log/slog"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type stackTraceError struct {
-	err        error
+	err error
+	// pcs holds the program counters captured when the error was created.
+	// They are resolved into stackTrace on first use, so that errors that are never inspected do not pay for symbolization.
+	pcs []uintptr
+	// omitted is the number of frames that were not captured because of the max depth (see GetOmittedFrames).
+	omitted    int
+	resolve    sync.Once
 	stackTrace StackTrace
 }
 
+func (e *stackTraceError) getStackTrace() StackTrace {
+	e.resolve.Do(func() {
+		if e.pcs != nil {
+			e.stackTrace = newStackTraceFromPCs(e.pcs)
+			e.pcs = nil
+		}
+	})
+	return e.stackTrace
+}
+
 func (e *stackTraceError) Error() string {
 	return e.err.Error()
 }
@@ -22,14 +41,32 @@ func (e *stackTraceError) Unwrap() error {
 	return e.err
 }
 
+// LogValue implements slog.LogValuer.
+//
+// The error is logged as a group that contains the error message and its StackTrace.
+func (e *stackTraceError) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("msg", e.Error()),
+		slog.Any("stacktrace", e.getStackTrace()),
+	)
+}
+
 // New creates an error with a StackTrace.
 func New(msg string) error {
-	return withStackTrace(errors.New(msg))
+	return withStackTrace(errors.New(msg), 0)
+}
+
+// NewWithSkip creates an error with a StackTrace that starts skip frames above the caller of NewWithSkip.
+//
+// It is intended for helper functions that create errors, so that the StackTrace starts at their callers.
+// If skip is 0, NewWithSkip works like New.
+func NewWithSkip(skip int, msg string) error {
+	return withStackTrace(errors.New(msg), max(skip, 0))
 }
 
 // Errorf creates an error with a StackTrace.
 func Errorf(format string, args ...any) error {
-	return withStackTrace(fmt.Errorf(format, args...))
+	return withStackTrace(fmt.Errorf(format, args...), 0)
 }
 
 // WithStackTrace creates an error with a StackTrace.
@@ -38,10 +75,36 @@ func Errorf(format string, args ...any) error {
 //
 // Also, if err is nil, this function returns nil.
 func WithStackTrace(err error) error {
-	return withStackTrace(err)
+	return withStackTrace(err, 0)
 }
 
-func withStackTrace(err error) error {
+// WithStackTraceSkip works like WithStackTrace, but the StackTrace starts skip frames above the caller of WithStackTraceSkip.
+//
+// It is intended for helper functions that wrap errors, so that the StackTrace starts at their callers.
+// If skip is 0, WithStackTraceSkip works like WithStackTrace.
+func WithStackTraceSkip(skip int, err error) error {
+	return withStackTrace(err, max(skip, 0))
+}
+
+// WithCustomStackTrace creates an error that has the given StackTrace instead of the current one.
+//
+// It is intended for errors whose StackTrace is known from elsewhere, such as tests that need fixed StackTraces.
+// Unlike WithStackTrace, the StackTrace is attached even if err already has one,
+// so GetAttachedStackTrace returns the given StackTrace.
+//
+// Also, if err is nil, this function returns nil.
+func WithCustomStackTrace(err error, stackTrace StackTrace) error {
+	if err == nil {
+		return nil
+	}
+
+	return &stackTraceError{
+		err:        err,
+		stackTrace: stackTrace,
+	}
+}
+
+func withStackTrace(err error, skip int) error {
 	if err == nil {
 		return nil
 	}
@@ -51,9 +114,11 @@ func withStackTrace(err error) error {
 		return err
 	}
 
+	pcs, omitted := callers(skip+2, MaxStackDepth()) // withStackTrace -> caller (New/Errorf/WithStackTrace)
 	return &stackTraceError{
-		err:        err,
-		stackTrace: newStackTraceFromCallers(2), // withStackTrace -> caller (New/Errorf/WithStackTrace)
+		err:     err,
+		pcs:     pcs,
+		omitted: omitted,
 	}
 }
 
@@ -85,7 +150,7 @@ func GetAttachedStackTrace(err error) (StackTrace, bool) {
 
 	serr := getStackTraceError(err)
 	if serr != nil {
-		return serr.stackTrace, true
+		return serr.getStackTrace(), true
 	}
 
 	return nil, false
@@ -139,11 +204,66 @@ func (s StackTrace) String() string {
 	return builder.String()
 }
 
+// LogValue implements slog.LogValuer.
+//
+// The StackTrace is logged as a group that contains the function names, file names and line numbers as arrays.
+func (s StackTrace) LogValue() slog.Value {
+	functions := make([]string, len(s))
+	files := make([]string, len(s))
+	lines := make([]int, len(s))
+	for i, funcInfo := range s {
+		functions[i] = funcInfo.Name
+		files[i] = funcInfo.File
+		lines[i] = funcInfo.Line
+	}
+	return slog.GroupValue(
+		slog.Any("functions", functions),
+		slog.Any("files", files),
+		slog.Any("lines", lines),
+	)
+}
+
 func newStackTraceFromCallers(skip int) StackTrace {
-	pcs := make([]uintptr, 64)
-	l := runtime.Callers(skip+2, pcs) // callers -> newStackTraceFromCallers
-	frames := runtime.CallersFrames(pcs[:l])
-	st := make(StackTrace, 0, l)
+	pcs, _ := callers(skip+1, MaxStackDepth()) // callers -> newStackTraceFromCallers
+	return newStackTraceFromPCs(pcs)
+}
+
+// callers returns the program counters of the stack of its caller, skipping skip frames.
+//
+// If depth is positive, at most depth program counters are returned, and the second result is the number of the omitted frames.
+// The number is -1 if it is not counted (see SetCountOmittedFrames).
+// If depth is 0, the whole stack is returned.
+func callers(skip, depth int) ([]uintptr, int) {
+	skip += 2 // runtime.Callers -> callers
+
+	// one more frame than the depth tells whether the stack is truncated
+	var buf [DefaultMaxStackDepth + 1]uintptr
+	pcs := buf[:]
+	if 0 < depth && depth+1 <= len(buf) {
+		pcs = buf[:depth+1]
+	} else if 0 < depth {
+		pcs = make([]uintptr, depth+1)
+	}
+	count := depth <= 0 || countOmittedFrames.Load()
+
+	for {
+		l := runtime.Callers(skip, pcs)
+		switch {
+		case l < len(pcs) && 0 < depth && depth < l:
+			return slices.Clone(pcs[:depth]), l - depth
+		case l < len(pcs):
+			return slices.Clone(pcs[:l]), 0
+		case !count:
+			return slices.Clone(pcs[:depth]), -1
+		}
+		// grow the buffer until the whole stack fits
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+}
+
+func newStackTraceFromPCs(pcs []uintptr) StackTrace {
+	frames := runtime.CallersFrames(pcs)
+	st := make(StackTrace, 0, len(pcs))
 
 	for {
 		frame, more := frames.Next()
@@ -169,28 +289,71 @@ func newStackTraceFromCallers(skip int) StackTrace {
 //
 // 2. If the given error has an Unwrap() error function, this function calls it and tries to process step 1 again
 // 3. If the given error has an Unwrap() []error function, this function calls it and tries to process step 1 for each error in the returned slice
+//
+// If the error is returned from a goroutine started by Go or Group.Go, the StackTrace of the goroutine that started it
+// is appended to the StackTraces of the error. If the error does not have any StackTrace, it is returned alone.
 func GetStackTraces(err error) iter.Seq2[error, StackTrace] {
 	return func(yield func(error, StackTrace) bool) {
-		tryYieldStackTrace(err, yield)
+		tryYieldStackTrace(err, nil, func(entries []StackTraceEntry) bool {
+			if len(entries) == 1 {
+				return yield(entries[0].Err, entries[0].StackTrace)
+			}
+			stackTrace := make(StackTrace, 0)
+			for _, entry := range entries {
+				stackTrace = append(stackTrace, entry.StackTrace...)
+			}
+			return yield(entries[0].Err, stackTrace)
+		})
 	}
 }
 
-func tryYieldStackTrace(err error, yield func(error, StackTrace) bool) bool {
+// GetStackTraceEntries returns the StackTraces returned by GetStackTraces as StackTraceEntry values,
+// so that the number of frames omitted from each StackTrace is also returned.
+//
+// Unlike GetStackTraces, the StackTrace of the goroutine that started the goroutine returning the error (see Go)
+// is returned as a separate entry right after the entry of the error, instead of being appended to its StackTrace.
+func GetStackTraceEntries(err error) iter.Seq[StackTraceEntry] {
+	return func(yield func(StackTraceEntry) bool) {
+		tryYieldStackTrace(err, nil, func(entries []StackTraceEntry) bool {
+			for _, entry := range entries {
+				if !yield(entry) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+// tryYieldStackTrace yields the entries of each StackTrace returned by GetStackTraces.
+// The StackTraces of the goroutines that started the goroutines returning the error follow the entry of the error.
+func tryYieldStackTrace(err error, path []int, yield func([]StackTraceEntry) bool) bool {
 	switch x := err.(type) {
 	case *stackTraceError:
-		return yield(x.err, x.stackTrace)
+		return yield([]StackTraceEntry{newStackTraceEntry(x.err, x.getStackTrace(), x.omitted, path)})
+	case *goroutineError:
+		createdBy := newStackTraceEntry(x.err, x.getCreatedBy(), x.omitted, path)
+		found := false
+		cont := tryYieldStackTrace(x.err, append(path, 0), func(entries []StackTraceEntry) bool {
+			found = true
+			return yield(append(entries, createdBy))
+		})
+		if !cont || found {
+			return cont
+		}
+		return yield([]StackTraceEntry{createdBy})
 	case interface{ Unwrap() error }:
 		err = x.Unwrap()
 		if err == nil {
 			return true
 		}
-		return tryYieldStackTrace(err, yield)
+		return tryYieldStackTrace(err, append(path, 0), yield)
 	case interface{ Unwrap() []error }:
-		for _, err := range x.Unwrap() {
+		for i, err := range x.Unwrap() {
 			if err == nil {
 				continue
 			}
-			if !tryYieldStackTrace(err, yield) {
+			if !tryYieldStackTrace(err, append(path, i), yield) {
 				return false
 			}
 		}
@@ -0,0 +1,59 @@
+package serrors
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = New("test")
+	}
+}
+
+func BenchmarkNew_deep(b *testing.B) {
+	for _, count := range []bool{false, true} {
+		for _, calls := range []int{200, 1000, 10000} {
+			name := strconv.Itoa(calls)
+			if count {
+				name += "/count"
+			}
+			b.Run(name, func(b *testing.B) {
+				SetCountOmittedFrames(count)
+				b.Cleanup(func() { SetCountOmittedFrames(false) })
+
+				b.ReportAllocs()
+				_ = recurse(calls, func() error {
+					for b.Loop() {
+						_ = New("test")
+					}
+					return nil
+				})
+			})
+		}
+	}
+}
+
+func BenchmarkErrorf(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = Errorf("test: %d", 1)
+	}
+}
+
+func BenchmarkWithStackTrace(b *testing.B) {
+	err := errors.New("test")
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = WithStackTrace(err)
+	}
+}
+
+func BenchmarkGetStackTrace(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = GetStackTrace(New("test"))
+	}
+}
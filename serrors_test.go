@@ -3,7 +3,10 @@ package serrors
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -58,6 +61,22 @@ func TestWithStackTrace(t *testing.T) {
 	}
 }
 
+func TestWithCustomStackTrace(t *testing.T) {
+	if err := WithCustomStackTrace(nil, StackTrace{}); err != nil {
+		t.Errorf("want nil, got %v", err)
+	}
+
+	stackTrace := StackTrace{{Name: "Test", File: "test.go", Line: 1}}
+	base := New("test")
+	got := WithCustomStackTrace(base, stackTrace)
+	if !errors.Is(got, base) {
+		t.Errorf("errors.Is(got, base) = false, want true")
+	}
+	if attached, ok := GetAttachedStackTrace(got); !ok || !reflect.DeepEqual(attached, stackTrace) {
+		t.Errorf("GetAttachedStackTrace() = (%v, %v), want %v", attached, ok, stackTrace)
+	}
+}
+
 func Test_getStackTraceError(t *testing.T) {
 	wrapped := New("wrapped")
 	tests := []struct {
@@ -234,6 +253,51 @@ func TestGetCurrentStackTrace(t *testing.T) {
 	}
 }
 
+func Test_stackTraceError_getStackTrace(t *testing.T) {
+	t.Run("resolve lazily", func(t *testing.T) {
+		serr := getStackTraceError(New("test"))
+		if serr.pcs == nil || serr.stackTrace != nil {
+			t.Fatalf("want unresolved stack trace, got pcs=%v, stackTrace=%v", serr.pcs, serr.stackTrace)
+		}
+
+		stackTrace := serr.getStackTrace()
+		if len(stackTrace) == 0 || stackTrace[0].Name != "github.com/Siroshun09/serrors.Test_stackTraceError_getStackTrace.func1" {
+			t.Errorf("getStackTrace() = %v, want to start with the caller of New", stackTrace)
+		}
+		if serr.pcs != nil {
+			t.Errorf("want pcs to be released, got %v", serr.pcs)
+		}
+		if got := serr.getStackTrace(); !reflect.DeepEqual(got, stackTrace) {
+			t.Errorf("getStackTrace() = %v, want %v", got, stackTrace)
+		}
+	})
+
+	t.Run("resolve concurrently", func(t *testing.T) {
+		serr := getStackTraceError(New("test"))
+		want := newStackTraceFromPCs(serr.pcs)
+
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if got := serr.getStackTrace(); !reflect.DeepEqual(got, want) {
+					t.Errorf("getStackTrace() = %v, want %v", got, want)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("already resolved", func(t *testing.T) {
+		stackTrace := StackTrace{{Name: "Test", File: "test.go", Line: 1}}
+		serr := &stackTraceError{err: errors.New("test"), stackTrace: stackTrace}
+		if got := serr.getStackTrace(); !reflect.DeepEqual(got, stackTrace) {
+			t.Errorf("getStackTrace() = %v, want %v", got, stackTrace)
+		}
+	})
+}
+
 func TestFuncInfo_String(t *testing.T) {
 
 	tests := []struct {
@@ -308,6 +372,58 @@ func TestStackTrace_String(t *testing.T) {
 	}
 }
 
+func TestStackTrace_LogValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		stackTrace StackTrace
+		want       string
+	}{
+		{
+			name:       "empty",
+			stackTrace: StackTrace{},
+			want:       `{"st":{"functions":[],"files":[],"lines":[]}}`,
+		},
+		{
+			name: "2 FuncInfo",
+			stackTrace: StackTrace{
+				{Name: "Test1", File: "test.go", Line: 1},
+				{Name: "Test2", File: "test.go", Line: 2},
+			},
+			want: `{"st":{"functions":["Test1","Test2"],"files":["test.go","test.go"],"lines":[1,2]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := logJSON(slog.Any("st", tt.stackTrace)); got != tt.want {
+				t.Errorf("LogValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStackTraceError_LogValue(t *testing.T) {
+	err := &stackTraceError{err: errors.New("test"), stackTrace: StackTrace{{Name: "Test", File: "test.go", Line: 1}}}
+	want := `{"err":{"msg":"test","stacktrace":{"functions":["Test"],"files":["test.go"],"lines":[1]}}}`
+	if got := logJSON(slog.Any("err", err)); got != want {
+		t.Errorf("LogValue() = %v, want %v", got, want)
+	}
+}
+
+// logJSON returns the JSON representation of attr without time, level and msg.
+func logJSON(attr slog.Attr) string {
+	var b strings.Builder
+	h := slog.NewJSONHandler(&b, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	slog.New(h).Info("", attr)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
 type multipleErrorsWrapper struct {
 	errs []error // possibly contains nil
 }
@@ -479,3 +595,57 @@ func TestGetStackTraces(t *testing.T) {
 		}
 	})
 }
+
+func TestGetStackTraceEntries(t *testing.T) {
+	stackTrace1 := StackTrace{{Name: "Test1", File: "test.go", Line: 1}}
+	stackTrace2 := StackTrace{{Name: "Test2", File: "test.go", Line: 2}}
+	base1 := errors.New("test1")
+	base2 := errors.New("test2")
+	truncated := &stackTraceError{err: base1, omitted: 2, stackTrace: stackTrace1}
+
+	tests := []struct {
+		name string
+		err  error
+		want []StackTraceEntry
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "joined errors",
+			err:  errors.Join(errors.New("plain"), fmt.Errorf("wrap: %w", truncated), &stackTraceError{err: base2, omitted: -1, stackTrace: stackTrace2}),
+			want: []StackTraceEntry{
+				{Err: base1, StackTrace: stackTrace1, Omitted: 2, Depth: 2, Path: []int{1, 0}},
+				{Err: base2, StackTrace: stackTrace2, Omitted: -1, Depth: 1, Path: []int{2}},
+			},
+		},
+		{
+			name: "goroutine error",
+			err:  &goroutineError{err: truncated, omitted: -1, createdBy: stackTrace2},
+			want: []StackTraceEntry{
+				{Err: base1, StackTrace: stackTrace1, Omitted: 2, Depth: 1, Path: []int{0}},
+				{Err: truncated, StackTrace: stackTrace2, Omitted: -1, Depth: 0, Path: nil},
+			},
+		},
+		{
+			name: "goroutine error without stack trace",
+			err:  &goroutineError{err: base2, createdBy: stackTrace2},
+			want: []StackTraceEntry{
+				{Err: base2, StackTrace: stackTrace2, Depth: 0, Path: nil},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []StackTraceEntry
+			for entry := range GetStackTraceEntries(tt.err) {
+				got = append(got, entry)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetStackTraceEntries() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
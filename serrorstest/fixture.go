@@ -0,0 +1,13 @@
+package serrorstest
+
+import "github.com/Siroshun09/serrors"
+
+// WithStackTrace returns an error that has the given StackTrace attached to err, as if the StackTrace had been captured there.
+//
+// It is intended for tests that need fixed StackTraces, such as tests of loggers and exporters whose output contains them.
+// The StackTrace is attached by serrors.WithCustomStackTrace, so the errors in the chain of err are kept as they are.
+//
+// If err is nil, this function returns nil.
+func WithStackTrace(err error, stackTrace serrors.StackTrace) error {
+	return serrors.WithCustomStackTrace(err, stackTrace)
+}
@@ -0,0 +1,46 @@
+package serrorstest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+)
+
+func TestWithStackTrace(t *testing.T) {
+	if err := WithStackTrace(nil, serrors.StackTrace{}); err != nil {
+		t.Errorf("WithStackTrace(nil) = %v, want nil", err)
+	}
+
+	inner := serrors.StackTrace{{Name: "inner", File: "inner.go", Line: 2}}
+	outer := serrors.StackTrace{{Name: "outer", File: "outer.go", Line: 1}}
+	err := WithStackTrace(fmt.Errorf("wrap: %w", WithStackTrace(errors.New("test"), inner)), outer)
+
+	if err.Error() != "wrap: test" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "wrap: test")
+	}
+	if stackTrace, ok := serrors.GetAttachedStackTrace(err); !ok || !reflect.DeepEqual(stackTrace, outer) {
+		t.Errorf("GetAttachedStackTrace() = (%v, %v), want %v", stackTrace, ok, outer)
+	}
+
+	var got []serrors.StackTrace
+	for entry := range serrors.GetAllStackTraces(err) {
+		got = append(got, entry.StackTrace)
+	}
+	if want := []serrors.StackTrace{outer, inner}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAllStackTraces() = %v, want %v", got, want)
+	}
+
+	if node := serrors.Tree(err); node.Type != "*fmt.wrapError" || node.Children[0].Type != "*errors.errorString" {
+		t.Errorf("Tree() = %s, want the original types", node)
+	}
+
+	pathErr := &fs.PathError{Op: "open", Path: "test", Err: fs.ErrNotExist}
+	var target *fs.PathError
+	if !errors.As(WithStackTrace(pathErr, outer), &target) || target != pathErr {
+		t.Errorf("errors.As() = %v, want %v", target, pathErr)
+	}
+}
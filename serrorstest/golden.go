@@ -0,0 +1,80 @@
+package serrorstest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertGolden update golden files instead of comparing them.
+//
+//	SERRORSTEST_UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "SERRORSTEST_UPDATE_GOLDEN"
+
+// AssertGolden compares got with the content of the golden file at path, and reports whether they are equal.
+//
+// got is usually the output of Normalize or NormalizeError.
+// If the environment variable UpdateGoldenEnv is set to a non-empty value,
+// AssertGolden writes got to the golden file (creating its directory if needed) and returns true.
+func AssertGolden(t testing.TB, path string, got string) bool {
+	t.Helper()
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := writeGolden(path, got); err != nil {
+			t.Errorf("failed to update the golden file: %v", err)
+			return false
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("golden file %s does not exist (run with %s=1 to create it)\ngot:\n%s", path, UpdateGoldenEnv, got)
+		return false
+	} else if err != nil {
+		t.Errorf("failed to read the golden file: %v", err)
+		return false
+	}
+
+	if string(want) != got {
+		t.Errorf("output does not match the golden file %s (run with %s=1 to update it)\n%s", path, UpdateGoldenEnv, diffLines(string(want), got))
+		return false
+	}
+	return true
+}
+
+func writeGolden(path string, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+// diffLines describes the first line that differs between want and got, followed by both texts.
+func diffLines(want string, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	line := 0
+	for line < len(wantLines) && line < len(gotLines) && wantLines[line] == gotLines[line] {
+		line++
+	}
+
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "first difference at line %d:\n", line+1)
+	_, _ = fmt.Fprintf(&b, "  want: %s\n", lineAt(wantLines, line))
+	_, _ = fmt.Fprintf(&b, "  got:  %s\n", lineAt(gotLines, line))
+	_, _ = fmt.Fprintf(&b, "want:\n%s\ngot:\n%s", want, got)
+	return b.String()
+}
+
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return fmt.Sprintf("%q", lines[i])
+	}
+	return "<EOF>"
+}
@@ -0,0 +1,107 @@
+package serrorstest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAssertGolden(t *testing.T) {
+	tests := []struct {
+		name     string
+		golden   string
+		got      string
+		update   bool
+		want     bool
+		wantFile string
+		wantMsgs []string
+	}{
+		{
+			name:     "equal",
+			golden:   "line1\nline2\n",
+			got:      "line1\nline2\n",
+			want:     true,
+			wantFile: "line1\nline2\n",
+		},
+		{
+			name:     "different",
+			golden:   "line1\nline2\n",
+			got:      "line1\nchanged\n",
+			want:     false,
+			wantFile: "line1\nline2\n",
+			wantMsgs: []string{
+				"run with " + UpdateGoldenEnv + "=1 to update it",
+				"first difference at line 2:",
+				`want: "line2"`,
+				`got:  "changed"`,
+			},
+		},
+		{
+			name:     "longer",
+			golden:   "line1",
+			got:      "line1\nline2",
+			want:     false,
+			wantFile: "line1",
+			wantMsgs: []string{`want: <EOF>`, `got:  "line2"`},
+		},
+		{
+			name:     "not exist",
+			got:      "line1\n",
+			want:     false,
+			wantMsgs: []string{"does not exist", "run with " + UpdateGoldenEnv + "=1 to create it"},
+		},
+		{
+			name:     "update",
+			golden:   "line1\n",
+			got:      "updated\n",
+			update:   true,
+			want:     true,
+			wantFile: "updated\n",
+		},
+		{
+			name:     "create",
+			got:      "created\n",
+			update:   true,
+			want:     true,
+			wantFile: "created\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.update {
+				t.Setenv(UpdateGoldenEnv, "1")
+			} else {
+				t.Setenv(UpdateGoldenEnv, "")
+			}
+
+			path := filepath.Join(t.TempDir(), "testdata", "test.golden")
+			if tt.golden != "" {
+				if err := writeGolden(path, tt.golden); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ft := &fakeT{}
+			if got := AssertGolden(ft, path, tt.got); got != tt.want || ft.failed == tt.want {
+				t.Errorf("AssertGolden() = %v, failed = %v, want %v", got, ft.failed, tt.want)
+			}
+			for _, msg := range tt.wantMsgs {
+				if !strings.Contains(ft.msg, msg) {
+					t.Errorf("message does not contain %q:\n%s", msg, ft.msg)
+				}
+			}
+
+			content, err := os.ReadFile(path)
+			if tt.wantFile == "" {
+				if !os.IsNotExist(err) {
+					t.Errorf("golden file exists: %q", content)
+				}
+				return
+			}
+			if string(content) != tt.wantFile {
+				t.Errorf("golden file = %q, want %q", content, tt.wantFile)
+			}
+		})
+	}
+}
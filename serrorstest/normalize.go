@@ -0,0 +1,144 @@
+package serrorstest
+
+import (
+	"go/build"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Siroshun09/serrors"
+)
+
+// LinePlaceholder is the placeholder that replaces line numbers in normalized output.
+const LinePlaceholder = "N"
+
+// NormalizeOption is the option for NormalizeWithOption.
+type NormalizeOption struct {
+	// KeepLineNumbers is whether to keep line numbers instead of replacing them with LinePlaceholder.
+	KeepLineNumbers bool
+	// KeepTestFrames is whether to keep the frames of the testing and runtime packages, such as testing.tRunner and runtime.goexit.
+	KeepTestFrames bool
+}
+
+// frameLine matches the frames rendered by FuncInfo.String, with the indentation of StackTrace.String, %+v and Node.String.
+var frameLine = regexp.MustCompile(`^([\s|]*)(\S+) \((.*):(\d+)\)$`)
+
+// Normalize normalizes the frames in s with the zero NormalizeOption.
+func Normalize(s string) string {
+	return NormalizeWithOption(s, NormalizeOption{})
+}
+
+// NormalizeWithOption normalizes the frames in s, so that the output does not depend on the machine or unrelated edits.
+//
+// s can be the output of StackTrace.String, the %+v format of errors, or Node.String.
+// In each frame ("name (file:line)"), the file path is shortened as follows:
+//
+//   - files in the current module are relative to the module root (the directory that contains go.mod)
+//   - files in GOROOT start with "$GOROOT/"
+//   - files in the module cache start with "$GOMODCACHE/"
+//
+// The line number is replaced with LinePlaceholder, and the frames of the testing and runtime packages are dropped.
+// Other lines are kept as-is.
+func NormalizeWithOption(s string, opt NormalizeOption) string {
+	lines := strings.SplitAfter(s, "\n")
+	normalized := make([]string, 0, len(lines))
+	for _, line := range lines {
+		body, newline := strings.CutSuffix(line, "\n")
+		m := frameLine.FindStringSubmatch(body)
+		if m == nil {
+			normalized = append(normalized, line)
+			continue
+		}
+
+		indent, name, file, lineNumber := m[1], m[2], m[3], m[4]
+		if !opt.KeepTestFrames && isTestFrame(name) {
+			continue
+		}
+		if !opt.KeepLineNumbers {
+			lineNumber = LinePlaceholder
+		}
+
+		body = indent + name + " (" + normalizePath(file) + ":" + lineNumber + ")"
+		if newline {
+			body += "\n"
+		}
+		normalized = append(normalized, body)
+	}
+	return strings.Join(normalized, "")
+}
+
+// NormalizeError renders the error chain of err by serrors.Tree and normalizes it with the zero NormalizeOption.
+//
+// If err is nil, NormalizeError returns an empty string.
+func NormalizeError(err error) string {
+	if err == nil {
+		return ""
+	}
+	return Normalize(serrors.Tree(err).String())
+}
+
+var keepFrame = serrors.DropPrefix("testing", "runtime")
+
+func isTestFrame(name string) bool {
+	return !keepFrame(serrors.FuncInfo{Name: name})
+}
+
+type pathPrefix struct {
+	dir         string
+	replacement string
+}
+
+var pathPrefixes = sync.OnceValue(func() []pathPrefix {
+	var prefixes []pathPrefix
+	if modCache := moduleCacheDir(); modCache != "" {
+		prefixes = append(prefixes, pathPrefix{dir: modCache, replacement: "$GOMODCACHE/"})
+	}
+	if goroot := build.Default.GOROOT; goroot != "" {
+		prefixes = append(prefixes, pathPrefix{dir: goroot, replacement: "$GOROOT/"})
+	}
+	if root := moduleRoot(); root != "" {
+		prefixes = append(prefixes, pathPrefix{dir: root, replacement: ""})
+	}
+	return prefixes
+})
+
+func normalizePath(file string) string {
+	for _, prefix := range pathPrefixes() {
+		if rest, ok := strings.CutPrefix(file, filepath.ToSlash(prefix.dir)+"/"); ok {
+			return prefix.replacement + rest
+		}
+	}
+	return file
+}
+
+func moduleCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	if gopath := filepath.SplitList(build.Default.GOPATH); 0 < len(gopath) && gopath[0] != "" {
+		return filepath.Join(gopath[0], "pkg", "mod")
+	}
+	return ""
+}
+
+// moduleRoot returns the directory that contains go.mod, searching from the working directory.
+// If go.mod is not found, the working directory is returned.
+func moduleRoot() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for dir := wd; ; {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return wd
+		}
+		dir = parent
+	}
+}
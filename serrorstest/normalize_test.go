@@ -0,0 +1,100 @@
+package serrorstest
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+)
+
+func TestNormalizeWithOption(t *testing.T) {
+	root := moduleRoot()
+	goroot := build.Default.GOROOT
+	modCache := moduleCacheDir()
+
+	stackTrace := serrors.StackTrace{
+		{Name: "github.com/Siroshun09/serrors/serrorstest.newOriginError", File: filepath.Join(root, "serrorstest", "serrorstest_test.go"), Line: 36},
+		{Name: "example.com/lib.Call", File: filepath.Join(modCache, "example.com", "lib@v1.0.0", "lib.go"), Line: 12},
+		{Name: "net/http.HandlerFunc.ServeHTTP", File: filepath.Join(goroot, "src", "net", "http", "server.go"), Line: 2294},
+		{Name: "testing.tRunner", File: filepath.Join(goroot, "src", "testing", "testing.go"), Line: 1934},
+		{Name: "runtime.goexit", File: filepath.Join(goroot, "src", "runtime", "asm_amd64.s"), Line: 1700},
+		{Name: "example.com/app.main", File: "/elsewhere/main.go", Line: 5},
+	}
+
+	tests := []struct {
+		name string
+		s    string
+		opt  NormalizeOption
+		want string
+	}{
+		{
+			name: "StackTrace.String",
+			s:    stackTrace.String(),
+			want: "github.com/Siroshun09/serrors/serrorstest.newOriginError (serrorstest/serrorstest_test.go:N)\n" +
+				"example.com/lib.Call ($GOMODCACHE/example.com/lib@v1.0.0/lib.go:N)\n" +
+				"net/http.HandlerFunc.ServeHTTP ($GOROOT/src/net/http/server.go:N)\n" +
+				"example.com/app.main (/elsewhere/main.go:N)",
+		},
+		{
+			name: "KeepLineNumbers",
+			s:    stackTrace[:1].String(),
+			opt:  NormalizeOption{KeepLineNumbers: true},
+			want: "github.com/Siroshun09/serrors/serrorstest.newOriginError (serrorstest/serrorstest_test.go:36)",
+		},
+		{
+			name: "KeepTestFrames",
+			s:    stackTrace[3:5].String(),
+			opt:  NormalizeOption{KeepTestFrames: true},
+			want: "testing.tRunner ($GOROOT/src/testing/testing.go:N)\nruntime.goexit ($GOROOT/src/runtime/asm_amd64.s:N)",
+		},
+		{
+			name: "tree",
+			s: "wrap: test [*fmt.wrapError]\n" +
+				"  stacktrace:\n" +
+				"    example.com/app.main (/elsewhere/main.go:5)\n" +
+				"`-- test [*errors.errorString]\n" +
+				"|     testing.tRunner (/usr/lib/go/src/testing/testing.go:1934)\n" +
+				"      example.com/app.run (/elsewhere/run.go:10)\n",
+			want: "wrap: test [*fmt.wrapError]\n" +
+				"  stacktrace:\n" +
+				"    example.com/app.main (/elsewhere/main.go:N)\n" +
+				"`-- test [*errors.errorString]\n" +
+				"      example.com/app.run (/elsewhere/run.go:N)\n",
+		},
+		{
+			name: "not a frame",
+			s:    "message (with parentheses)\nkey=value (file.go:1) trailing\n",
+			want: "message (with parentheses)\nkey=value (file.go:1) trailing\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeWithOption(tt.s, tt.opt); got != tt.want {
+				t.Errorf("NormalizeWithOption() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeError(t *testing.T) {
+	if got := NormalizeError(nil); got != "" {
+		t.Errorf("NormalizeError(nil) = %q, want empty", got)
+	}
+
+	err := fmt.Errorf("outer: %w", newOriginError())
+	AssertGolden(t, filepath.Join("testdata", "chain.golden"), NormalizeError(err))
+	AssertGolden(t, filepath.Join("testdata", "verbose.golden"), Normalize(fmt.Sprintf("%+v", newOriginError())))
+}
+
+func TestModuleRoot(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := moduleRoot(), filepath.Dir(wd); got != want {
+		t.Errorf("moduleRoot() = %q, want %q", got, want)
+	}
+}
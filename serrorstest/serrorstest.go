@@ -0,0 +1,79 @@
+// Package serrorstest provides test assertions for errors created by serrors.
+//
+// The failure messages contain the whole error chain and its StackTraces rendered by serrors.Tree.
+package serrorstest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+)
+
+// RequireStackTrace returns the StackTrace attached to err (see serrors.GetAttachedStackTrace).
+//
+// If err does not have a StackTrace, the test fails and stops by t.Fatalf.
+func RequireStackTrace(t testing.TB, err error) serrors.StackTrace {
+	t.Helper()
+
+	stackTrace, ok := serrors.GetAttachedStackTrace(err)
+	if !ok {
+		t.Fatalf("error does not have a stack trace\n%s", describe(err))
+	}
+	return stackTrace
+}
+
+// AssertOrigin checks that err originated in the function fn, and reports whether the assertion succeeded.
+//
+// The origin is the first frame of the StackTrace attached to err.
+// fn is compared with the full name (such as "github.com/x/y/pkg.Func") and the short name (such as "pkg.Func") of the frame.
+func AssertOrigin(t testing.TB, err error, fn string) bool {
+	t.Helper()
+
+	stackTrace, ok := serrors.GetAttachedStackTrace(err)
+	if !ok || len(stackTrace) == 0 {
+		t.Errorf("error does not have a stack trace, want origin %q\n%s", fn, describe(err))
+		return false
+	}
+
+	origin := stackTrace[0]
+	if origin.Name != fn && origin.ShortName() != fn {
+		t.Errorf("error originated in %q, want %q\n%s", origin.ShortName(), fn, describe(err))
+		return false
+	}
+	return true
+}
+
+// AssertChain checks that the error chain of err contains all of the errors in want (see errors.Is),
+// and reports whether the assertion succeeded.
+func AssertChain(t testing.TB, err error, want ...error) bool {
+	t.Helper()
+
+	var missing []string
+	for _, target := range want {
+		if !errors.Is(err, target) {
+			missing = append(missing, describeTarget(target))
+		}
+	}
+
+	if 0 < len(missing) {
+		t.Errorf("error chain does not contain %s\n%s", strings.Join(missing, ", "), describe(err))
+		return false
+	}
+	return true
+}
+
+func describeTarget(target error) string {
+	if target == nil {
+		return "<nil>"
+	}
+	return "\"" + target.Error() + "\""
+}
+
+func describe(err error) string {
+	if err == nil {
+		return "error: <nil>"
+	}
+	return "error chain:\n" + serrors.Tree(err).String()
+}
@@ -0,0 +1,177 @@
+package serrorstest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+)
+
+// fakeT records failures instead of failing the test.
+type fakeT struct {
+	testing.TB
+	failed bool
+	fatal  bool
+	msg    string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.failed = true
+	t.msg = fmt.Sprintf(format, args...)
+}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+	t.Errorf(format, args...)
+	t.fatal = true
+}
+
+var errSentinel = errors.New("sentinel")
+
+//go:noinline
+func newOriginError() error {
+	return serrors.Errorf("wrap: %w", errSentinel)
+}
+
+func TestRequireStackTrace(t *testing.T) {
+	t.Run("attached", func(t *testing.T) {
+		err := newOriginError()
+		ft := &fakeT{}
+		if got := RequireStackTrace(ft, err); got.String() != serrors.GetStackTrace(err).String() || ft.failed {
+			t.Errorf("RequireStackTrace() = %v, failed = %v", got, ft.failed)
+		}
+	})
+
+	t.Run("not attached", func(t *testing.T) {
+		ft := &fakeT{}
+		RequireStackTrace(ft, errors.New("test"))
+		if !ft.fatal {
+			t.Error("RequireStackTrace() did not call Fatalf")
+		}
+		want := "error does not have a stack trace\nerror chain:\ntest [*errors.errorString]\n"
+		if ft.msg != want {
+			t.Errorf("message = %q, want %q", ft.msg, want)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		ft := &fakeT{}
+		RequireStackTrace(ft, nil)
+		if !ft.fatal || ft.msg != "error does not have a stack trace\nerror: <nil>" {
+			t.Errorf("fatal = %v, message = %q", ft.fatal, ft.msg)
+		}
+	})
+}
+
+func TestAssertOrigin(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		fn       string
+		want     bool
+		wantMsgs []string
+	}{
+		{
+			name: "short name",
+			err:  newOriginError(),
+			fn:   "serrorstest.newOriginError",
+			want: true,
+		},
+		{
+			name: "full name",
+			err:  newOriginError(),
+			fn:   "github.com/Siroshun09/serrors/serrorstest.newOriginError",
+			want: true,
+		},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("outer: %w", newOriginError()),
+			fn:   "serrorstest.newOriginError",
+			want: true,
+		},
+		{
+			name: "different function",
+			err:  newOriginError(),
+			fn:   "serrorstest.other",
+			want: false,
+			wantMsgs: []string{
+				`error originated in "serrorstest.newOriginError", want "serrorstest.other"`,
+				"wrap: sentinel [*fmt.wrapError]",
+				"stacktrace:",
+				"serrorstest.newOriginError",
+				"`-- sentinel [*errors.errorString]",
+			},
+		},
+		{
+			name:     "no stack trace",
+			err:      errors.New("test"),
+			fn:       "serrorstest.newOriginError",
+			want:     false,
+			wantMsgs: []string{`error does not have a stack trace, want origin "serrorstest.newOriginError"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := &fakeT{}
+			if got := AssertOrigin(ft, tt.err, tt.fn); got != tt.want || ft.failed == tt.want {
+				t.Errorf("AssertOrigin() = %v, failed = %v, want %v", got, ft.failed, tt.want)
+			}
+			for _, msg := range tt.wantMsgs {
+				if !strings.Contains(ft.msg, msg) {
+					t.Errorf("message does not contain %q:\n%s", msg, ft.msg)
+				}
+			}
+		})
+	}
+}
+
+func TestAssertChain(t *testing.T) {
+	errOther := errors.New("other")
+
+	tests := []struct {
+		name    string
+		err     error
+		want    []error
+		ok      bool
+		wantMsg string
+	}{
+		{
+			name: "contains",
+			err:  fmt.Errorf("outer: %w", newOriginError()),
+			want: []error{errSentinel},
+			ok:   true,
+		},
+		{
+			name: "joined",
+			err:  errors.Join(errSentinel, errOther),
+			want: []error{errSentinel, errOther},
+			ok:   true,
+		},
+		{
+			name: "no targets",
+			err:  errors.New("test"),
+			ok:   true,
+		},
+		{
+			name:    "missing",
+			err:     errors.New("test"),
+			want:    []error{errSentinel, errOther, nil},
+			ok:      false,
+			wantMsg: "error chain does not contain \"sentinel\", \"other\", <nil>\nerror chain:\ntest [*errors.errorString]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := &fakeT{}
+			if got := AssertChain(ft, tt.err, tt.want...); got != tt.ok || ft.failed == tt.ok {
+				t.Errorf("AssertChain() = %v, failed = %v, want %v", got, ft.failed, tt.ok)
+			}
+			if ft.msg != tt.wantMsg {
+				t.Errorf("message = %q, want %q", ft.msg, tt.wantMsg)
+			}
+		})
+	}
+}
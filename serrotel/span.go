@@ -0,0 +1,62 @@
+// Package serrotel provides OpenTelemetry integration for errors created by serrors.
+package serrotel
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Siroshun09/serrors"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RecordError records err as an exception event of span and sets the status of span to codes.Error.
+//
+// Unlike trace.Span.RecordError with trace.WithStackTrace, the exception.stacktrace attribute is built from
+// the StackTraces attached to err (see serrors.GetStackTraces), so it points at the place where err was created
+// instead of the place where it is recorded.
+// The StackTraces are filtered by serrors.DefaultFrameFilters, and written in the same format as Go's tracebacks.
+// If err does not have a StackTrace, the attribute is omitted.
+//
+// The exception.type attribute is the concrete type name of err, without the wrappers created by serrors.
+//
+// If err is nil or span is not recording, this function does nothing.
+func RecordError(span trace.Span, err error, options ...trace.EventOption) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+
+	options = append(options, trace.WithAttributes(
+		semconv.ExceptionType(serrors.Tree(err).Type),
+		semconv.ExceptionMessage(err.Error()),
+	))
+	if stackTrace := formatStackTraces(err); stackTrace != "" {
+		options = append(options, trace.WithAttributes(semconv.ExceptionStacktrace(stackTrace)))
+	}
+
+	span.AddEvent(semconv.ExceptionEventName, options...)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// formatStackTraces formats the StackTraces of err like the tracebacks printed by runtime/debug.Stack.
+//
+// Multiple StackTraces are separated by an empty line, and truncated ones end with "...N frames elided..." as the runtime does.
+func formatStackTraces(err error) string {
+	var b strings.Builder
+	for entry := range serrors.GetStackTraceEntries(err) {
+		if 0 < b.Len() {
+			b.WriteString("\n")
+		}
+		for _, funcInfo := range entry.StackTrace.FilterDefault() {
+			b.WriteString(funcInfo.Name + "(...)\n\t" + funcInfo.File + ":" + strconv.Itoa(funcInfo.Line) + "\n")
+		}
+		switch {
+		case 0 < entry.Omitted:
+			b.WriteString("..." + strconv.Itoa(entry.Omitted) + " frames elided...\n")
+		case entry.Omitted < 0:
+			b.WriteString("...additional frames elided...\n")
+		}
+	}
+	return b.String()
+}
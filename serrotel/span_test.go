@@ -0,0 +1,176 @@
+package serrotel_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+	"github.com/Siroshun09/serrors/serrorstest"
+	"github.com/Siroshun09/serrors/serrotel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func recordError(t *testing.T, err error, options ...trace.EventOption) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	_, span := provider.Tracer("test").Start(t.Context(), "test")
+	serrotel.RecordError(span, err, options...)
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("len(spans) = %d, want 1", len(spans))
+	}
+	return spans[0]
+}
+
+func TestRecordError(t *testing.T) {
+	stackTrace1 := serrors.StackTrace{
+		{Name: "example.com/app.handle", File: "/src/app/handler.go", Line: 10},
+		{Name: "example.com/app.main", File: "/src/app/main.go", Line: 20},
+	}
+	stackTrace2 := serrors.StackTrace{
+		{Name: "example.com/app.serve", File: "/src/app/server.go", Line: 30},
+	}
+
+	tests := []struct {
+		name  string
+		err   error
+		attrs []attribute.KeyValue
+	}{
+		{
+			name: "stack trace attached",
+			err:  fmt.Errorf("wrap: %w", serrorstest.WithStackTrace(errors.New("test"), stackTrace1)),
+			attrs: []attribute.KeyValue{
+				attribute.String("exception.type", "*fmt.wrapError"),
+				attribute.String("exception.message", "wrap: test"),
+				attribute.String("exception.stacktrace", strings.Join([]string{
+					"example.com/app.handle(...)",
+					"\t/src/app/handler.go:10",
+					"example.com/app.main(...)",
+					"\t/src/app/main.go:20",
+					"",
+				}, "\n")),
+			},
+		},
+		{
+			name: "multiple stack traces",
+			err: errors.Join(
+				serrorstest.WithStackTrace(errors.New("test1"), stackTrace1),
+				serrorstest.WithStackTrace(errors.New("test2"), stackTrace2),
+			),
+			attrs: []attribute.KeyValue{
+				attribute.String("exception.type", "*errors.joinError"),
+				attribute.String("exception.message", "test1\ntest2"),
+				attribute.String("exception.stacktrace", strings.Join([]string{
+					"example.com/app.handle(...)",
+					"\t/src/app/handler.go:10",
+					"example.com/app.main(...)",
+					"\t/src/app/main.go:20",
+					"",
+					"example.com/app.serve(...)",
+					"\t/src/app/server.go:30",
+					"",
+				}, "\n")),
+			},
+		},
+		{
+			name: "truncated stack trace",
+			err: serrors.Decode(&serrors.Envelope{
+				Message:    "test",
+				Kind:       serrors.EnvelopeKindStackTrace,
+				StackTrace: stackTrace2,
+				Omitted:    3,
+				Causes:     []*serrors.Envelope{{Message: "test", Type: "*errors.errorString"}},
+			}),
+			attrs: []attribute.KeyValue{
+				attribute.String("exception.type", "*errors.errorString"),
+				attribute.String("exception.message", "test"),
+				attribute.String("exception.stacktrace", strings.Join([]string{
+					"example.com/app.serve(...)",
+					"\t/src/app/server.go:30",
+					"...3 frames elided...",
+					"",
+				}, "\n")),
+			},
+		},
+		{
+			name: "stack trace not attached",
+			err:  errors.New("test"),
+			attrs: []attribute.KeyValue{
+				attribute.String("exception.type", "*errors.errorString"),
+				attribute.String("exception.message", "test"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span := recordError(t, tt.err)
+
+			if span.Status().Code != codes.Error || span.Status().Description != tt.err.Error() {
+				t.Errorf("Status() = %+v, want %v: %s", span.Status(), codes.Error, tt.err.Error())
+			}
+
+			events := span.Events()
+			if len(events) != 1 {
+				t.Fatalf("len(Events()) = %d, want 1", len(events))
+			}
+			if events[0].Name != "exception" {
+				t.Errorf("Name = %q, want exception", events[0].Name)
+			}
+			if !reflect.DeepEqual(events[0].Attributes, tt.attrs) {
+				t.Errorf("Attributes = %v, want %v", events[0].Attributes, tt.attrs)
+			}
+		})
+	}
+
+	t.Run("origin", func(t *testing.T) {
+		err := serrors.New("test")
+		span := recordError(t, err)
+
+		for _, attr := range span.Events()[0].Attributes {
+			if attr.Key != "exception.stacktrace" {
+				continue
+			}
+			first, _, _ := strings.Cut(attr.Value.AsString(), "\n")
+			if want := serrors.GetStackTrace(err)[0].Name + "(...)"; first != want {
+				t.Errorf("first line = %q, want %q", first, want)
+			}
+			return
+		}
+		t.Error("exception.stacktrace is not recorded")
+	})
+
+	t.Run("event options", func(t *testing.T) {
+		span := recordError(t, errors.New("test"), trace.WithAttributes(attribute.String("key", "value")))
+
+		attrs := span.Events()[0].Attributes
+		if len(attrs) != 3 || attrs[0] != attribute.String("key", "value") {
+			t.Errorf("Attributes = %v, want key=value first", attrs)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		span := recordError(t, nil)
+
+		if len(span.Events()) != 0 || span.Status().Code != codes.Unset {
+			t.Errorf("Events() = %v, Status() = %+v, want nothing recorded", span.Events(), span.Status())
+		}
+	})
+
+	t.Run("not recording", func(t *testing.T) {
+		_, span := noop.NewTracerProvider().Tracer("test").Start(t.Context(), "test")
+		// should not panic
+		serrotel.RecordError(span, serrors.New("test"))
+	})
+}
@@ -0,0 +1,214 @@
+// Package serrsentry converts errors created by serrors into Sentry events and sends them to a Sentry server.
+package serrsentry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Siroshun09/serrors"
+)
+
+// Event is a Sentry event payload.
+//
+// Only a subset of the fields is defined.
+// ServerName, Release and Environment are not set by NewEvent, and can be set before sending the event.
+type Event struct {
+	EventID     string         `json:"event_id"`
+	Timestamp   time.Time      `json:"timestamp"`
+	Platform    string         `json:"platform"`
+	Level       string         `json:"level"`
+	ServerName  string         `json:"server_name,omitempty"`
+	Release     string         `json:"release,omitempty"`
+	Environment string         `json:"environment,omitempty"`
+	Exception   ExceptionList  `json:"exception"`
+	Extra       map[string]any `json:"extra,omitempty"`
+	Fingerprint []string       `json:"fingerprint,omitempty"`
+}
+
+// ExceptionList is the exception interface of Event.
+type ExceptionList struct {
+	// Values are the exceptions sorted from the innermost error to the outermost error.
+	Values []Exception `json:"values"`
+}
+
+// Exception is an error in the error tree.
+type Exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Mechanism  *Mechanism  `json:"mechanism,omitempty"`
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+// Mechanism describes the position of an Exception in the error tree.
+type Mechanism struct {
+	Type             string `json:"type"`
+	Handled          *bool  `json:"handled,omitempty"`
+	ExceptionID      int    `json:"exception_id"`
+	ParentID         *int   `json:"parent_id,omitempty"`
+	IsExceptionGroup bool   `json:"is_exception_group,omitempty"`
+}
+
+// Stacktrace is the stack trace of an Exception.
+type Stacktrace struct {
+	// Frames are sorted from the outermost caller to the function that created the error (callee-last).
+	Frames []Frame `json:"frames"`
+}
+
+// Frame is a frame of a Stacktrace.
+type Frame struct {
+	Function string `json:"function,omitempty"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	AbsPath  string `json:"abs_path,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+// EventOption is the option for NewEventWithOption.
+type EventOption struct {
+	// InAppPrefixes are the module paths whose frames are marked as in_app.
+	//
+	// If empty, all frames except the standard library are marked as in_app.
+	InAppPrefixes []string
+	// UseFingerprint is whether to set serrors.Fingerprint to Event.Fingerprint.
+	//
+	// If true, Sentry groups events by the fingerprint instead of its own grouping algorithm.
+	UseFingerprint bool
+}
+
+// NewEvent creates a new Event from err.
+func NewEvent(err error) *Event {
+	return NewEventWithOption(err, EventOption{})
+}
+
+// NewEventWithOption creates a new Event from err with the given option.
+//
+// Each error in the error tree (see serrors.Tree) is converted into an Exception with the same StackTraces as serrors.GetStackTraces.
+// The Exceptions are linked by their Mechanism, so joined errors are shown as exception groups.
+// The attributes returned by serrors.Attributes are set to Event.Extra.
+//
+// If err is nil, the Event does not have any Exception.
+func NewEventWithOption(err error, opt EventOption) *Event {
+	event := &Event{
+		EventID:   newEventID(),
+		Timestamp: time.Now().UTC(),
+		Platform:  "go",
+		Level:     "error",
+		Exception: ExceptionList{Values: []Exception{}},
+	}
+
+	if node := serrors.Tree(err); node != nil {
+		event.Exception.Values = appendExceptions(event.Exception.Values, node, nil, opt)
+		// Sentry expects the exceptions from the innermost error to the outermost error
+		slices.Reverse(event.Exception.Values)
+	}
+
+	if attrs := serrors.Attributes(err); 0 < len(attrs) {
+		event.Extra = make(map[string]any, len(attrs))
+		for _, attr := range attrs {
+			event.Extra[attr.Key] = serrors.AttrValue(attr.Value)
+		}
+	}
+
+	if opt.UseFingerprint && err != nil {
+		event.Fingerprint = []string{serrors.Fingerprint(err)}
+	}
+
+	return event
+}
+
+func appendExceptions(exceptions []Exception, node *serrors.Node, parentID *int, opt EventOption) []Exception {
+	id := len(exceptions)
+	exception := Exception{
+		Type:  node.Type,
+		Value: node.Message,
+		Mechanism: &Mechanism{
+			Type:             "generic",
+			ExceptionID:      id,
+			ParentID:         parentID,
+			IsExceptionGroup: 1 < len(node.Children),
+		},
+	}
+	if parentID != nil {
+		exception.Mechanism.Type = "chained"
+	}
+
+	if _, ok := node.Err.(*serrors.PanicError); ok {
+		handled := false
+		exception.Mechanism.Type = "panic"
+		exception.Mechanism.Handled = &handled
+	}
+
+	if 0 < len(node.StackTrace) || 0 < len(node.CreatedBy) {
+		exception.Stacktrace = newStacktrace(node, opt)
+	}
+
+	exceptions = append(exceptions, exception)
+	for _, child := range node.Children {
+		exceptions = appendExceptions(exceptions, child, &id, opt)
+	}
+	return exceptions
+}
+
+func newStacktrace(node *serrors.Node, opt EventOption) *Stacktrace {
+	frames := make([]Frame, 0, len(node.StackTrace)+len(node.CreatedBy)+2)
+	frames = appendFrames(frames, node.StackTrace, node.Omitted, opt)
+	frames = appendFrames(frames, node.CreatedBy, node.CreatedByOmitted, opt)
+	// serrors.StackTrace is callee-first, but Sentry expects callee-last
+	slices.Reverse(frames)
+	return &Stacktrace{Frames: frames}
+}
+
+// appendFrames appends the Frames of stackTrace, followed by a marker Frame such as "... 3 more frames"
+// if omitted frames were cut off from the caller side of it.
+func appendFrames(frames []Frame, stackTrace serrors.StackTrace, omitted int, opt EventOption) []Frame {
+	for _, funcInfo := range stackTrace {
+		frames = append(frames, newFrame(funcInfo, opt))
+	}
+	if omitted != 0 {
+		frames = append(frames, Frame{Function: serrors.FormatOmittedFrames(omitted)})
+	}
+	return frames
+}
+
+func newFrame(funcInfo serrors.FuncInfo, opt EventOption) Frame {
+	module := funcInfo.Package()
+	function := funcInfo.Name
+	if module != "" {
+		function = strings.TrimPrefix(funcInfo.ShortName(), path.Base(module)+".")
+	}
+
+	return Frame{
+		Function: function,
+		Module:   module,
+		Filename: funcInfo.File,
+		AbsPath:  funcInfo.File,
+		Lineno:   funcInfo.Line,
+		InApp:    isInApp(funcInfo, opt.InAppPrefixes),
+	}
+}
+
+func isInApp(funcInfo serrors.FuncInfo, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return serrors.WithoutStdlib()(funcInfo)
+	}
+
+	module := funcInfo.Package()
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if module == prefix || strings.HasPrefix(module, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func newEventID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
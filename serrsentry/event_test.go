@@ -0,0 +1,220 @@
+package serrsentry_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+	"github.com/Siroshun09/serrors/serrorstest"
+	"github.com/Siroshun09/serrors/serrsentry"
+)
+
+var testStackTrace = serrors.StackTrace{
+	{Name: "example.com/app/internal/db.(*Conn).Query.func1", File: "/src/app/internal/db/conn.go", Line: 30},
+	{Name: "example.com/app.handle", File: "/src/app/handler.go", Line: 10},
+	{Name: "net/http.HandlerFunc.ServeHTTP", File: "/usr/local/go/src/net/http/server.go", Line: 2220},
+	{Name: "runtime.goexit", File: "/usr/local/go/src/runtime/asm_amd64.s", Line: 1700},
+}
+
+// newTestError creates "wrap: test" that wraps an error with testStackTrace.
+func newTestError() error {
+	return fmt.Errorf("wrap: %w", serrorstest.WithStackTrace(errors.New("test"), testStackTrace))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestNewEventWithOption(t *testing.T) {
+	frames := []serrsentry.Frame{
+		{Function: "goexit", Module: "runtime", Filename: "/usr/local/go/src/runtime/asm_amd64.s", AbsPath: "/usr/local/go/src/runtime/asm_amd64.s", Lineno: 1700, InApp: false},
+		{Function: "HandlerFunc.ServeHTTP", Module: "net/http", Filename: "/usr/local/go/src/net/http/server.go", AbsPath: "/usr/local/go/src/net/http/server.go", Lineno: 2220, InApp: false},
+		{Function: "handle", Module: "example.com/app", Filename: "/src/app/handler.go", AbsPath: "/src/app/handler.go", Lineno: 10, InApp: true},
+		{Function: "(*Conn).Query.func1", Module: "example.com/app/internal/db", Filename: "/src/app/internal/db/conn.go", AbsPath: "/src/app/internal/db/conn.go", Lineno: 30, InApp: true},
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		opt  serrsentry.EventOption
+		want []serrsentry.Exception
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: []serrsentry.Exception{},
+		},
+		{
+			name: "wrapped error",
+			err:  newTestError(),
+			want: []serrsentry.Exception{
+				{
+					Type:       "*errors.errorString",
+					Value:      "test",
+					Mechanism:  &serrsentry.Mechanism{Type: "chained", ExceptionID: 1, ParentID: ptr(0)},
+					Stacktrace: &serrsentry.Stacktrace{Frames: frames},
+				},
+				{
+					Type:      "*fmt.wrapError",
+					Value:     "wrap: test",
+					Mechanism: &serrsentry.Mechanism{Type: "generic", ExceptionID: 0},
+				},
+			},
+		},
+		{
+			name: "InAppPrefixes",
+			err:  newTestError(),
+			opt:  serrsentry.EventOption{InAppPrefixes: []string{"example.com/app/internal/"}},
+			want: []serrsentry.Exception{
+				{
+					Type:      "*errors.errorString",
+					Value:     "test",
+					Mechanism: &serrsentry.Mechanism{Type: "chained", ExceptionID: 1, ParentID: ptr(0)},
+					Stacktrace: &serrsentry.Stacktrace{Frames: []serrsentry.Frame{
+						frames[0],
+						frames[1],
+						{Function: "handle", Module: "example.com/app", Filename: "/src/app/handler.go", AbsPath: "/src/app/handler.go", Lineno: 10, InApp: false},
+						frames[3],
+					}},
+				},
+				{
+					Type:      "*fmt.wrapError",
+					Value:     "wrap: test",
+					Mechanism: &serrsentry.Mechanism{Type: "generic", ExceptionID: 0},
+				},
+			},
+		},
+		{
+			name: "truncated stack trace",
+			err: serrors.Decode(&serrors.Envelope{
+				Message:    "test",
+				Kind:       serrors.EnvelopeKindStackTrace,
+				StackTrace: testStackTrace[:2],
+				Omitted:    2,
+				Causes:     []*serrors.Envelope{{Message: "test", Type: "*errors.errorString"}},
+			}),
+			want: []serrsentry.Exception{
+				{
+					Type:      "*errors.errorString",
+					Value:     "test",
+					Mechanism: &serrsentry.Mechanism{Type: "generic", ExceptionID: 0},
+					Stacktrace: &serrsentry.Stacktrace{Frames: []serrsentry.Frame{
+						{Function: "... 2 more frames"},
+						frames[2],
+						frames[3],
+					}},
+				},
+			},
+		},
+		{
+			name: "joined errors",
+			err:  errors.Join(errors.New("test1"), errors.New("test2")),
+			want: []serrsentry.Exception{
+				{
+					Type:      "*errors.errorString",
+					Value:     "test2",
+					Mechanism: &serrsentry.Mechanism{Type: "chained", ExceptionID: 2, ParentID: ptr(0)},
+				},
+				{
+					Type:      "*errors.errorString",
+					Value:     "test1",
+					Mechanism: &serrsentry.Mechanism{Type: "chained", ExceptionID: 1, ParentID: ptr(0)},
+				},
+				{
+					Type:      "*errors.joinError",
+					Value:     "test1\ntest2",
+					Mechanism: &serrsentry.Mechanism{Type: "generic", ExceptionID: 0, IsExceptionGroup: true},
+				},
+			},
+		},
+		{
+			name: "panic",
+			err:  &serrors.PanicError{Value: "test"},
+			want: []serrsentry.Exception{
+				{
+					Type:      "*serrors.PanicError",
+					Value:     "panic: test",
+					Mechanism: &serrsentry.Mechanism{Type: "panic", Handled: ptr(false), ExceptionID: 0},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := serrsentry.NewEventWithOption(tt.err, tt.opt)
+			if !reflect.DeepEqual(event.Exception.Values, tt.want) {
+				t.Errorf("Exception.Values = %+v, want %+v", event.Exception.Values, tt.want)
+			}
+			if event.Platform != "go" || event.Level != "error" {
+				t.Errorf("Platform = %q, Level = %q, want go, error", event.Platform, event.Level)
+			}
+			if len(event.EventID) != 32 {
+				t.Errorf("EventID = %q, want 32 hex characters", event.EventID)
+			}
+			if event.Timestamp.IsZero() {
+				t.Error("Timestamp is zero")
+			}
+		})
+	}
+
+	t.Run("extra", func(t *testing.T) {
+		err := serrors.With(newTestError(), "user_id", 42, "cause", errors.New("inner"))
+		event := serrsentry.NewEvent(err)
+
+		want := map[string]any{"user_id": int64(42), "cause": "inner"}
+		if !reflect.DeepEqual(event.Extra, want) {
+			t.Errorf("Extra = %v, want %v", event.Extra, want)
+		}
+		if event.Fingerprint != nil {
+			t.Errorf("Fingerprint = %v, want nil", event.Fingerprint)
+		}
+	})
+
+	t.Run("UseFingerprint", func(t *testing.T) {
+		err := newTestError()
+		event := serrsentry.NewEventWithOption(err, serrsentry.EventOption{UseFingerprint: true})
+
+		want := []string{serrors.Fingerprint(err)}
+		if !reflect.DeepEqual(event.Fingerprint, want) {
+			t.Errorf("Fingerprint = %v, want %v", event.Fingerprint, want)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(serrsentry.NewEvent(newTestError()))
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+
+		var got struct {
+			Exception struct {
+				Values []struct {
+					Stacktrace *struct {
+						Frames []map[string]any `json:"frames"`
+					} `json:"stacktrace"`
+				} `json:"values"`
+			} `json:"exception"`
+		}
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+
+		want := map[string]any{
+			"function": "(*Conn).Query.func1",
+			"module":   "example.com/app/internal/db",
+			"filename": "/src/app/internal/db/conn.go",
+			"abs_path": "/src/app/internal/db/conn.go",
+			"lineno":   float64(30),
+			"in_app":   true,
+		}
+		if len(got.Exception.Values) != 2 || got.Exception.Values[0].Stacktrace == nil {
+			t.Fatalf("unexpected JSON: %s", data)
+		}
+		if frames := got.Exception.Values[0].Stacktrace.Frames; !reflect.DeepEqual(frames[len(frames)-1], want) {
+			t.Errorf("last frame = %v, want %v", frames[len(frames)-1], want)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package serrsentry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Siroshun09/serrors"
+)
+
+const (
+	sentryVersion = "7"
+	sentryClient  = "serrors/1.0"
+)
+
+// DSN is a parsed Sentry DSN, such as "https://public@sentry.example.com/1".
+type DSN struct {
+	// Scheme is the scheme of the Sentry server, "http" or "https".
+	Scheme string
+	// PublicKey is the public key of the project.
+	PublicKey string
+	// SecretKey is the secret key of the project, which is only used by old Sentry servers.
+	SecretKey string
+	// Host is the host (and port) of the Sentry server.
+	Host string
+	// Path is the path prefix of the Sentry server, if it is not served at the root.
+	Path string
+	// ProjectID is the ID of the project.
+	ProjectID string
+}
+
+// ParseDSN parses the Sentry DSN.
+func ParseDSN(dsn string) (*DSN, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, serrors.Errorf("invalid DSN: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, serrors.Errorf("invalid DSN scheme: %q", u.Scheme)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, serrors.Errorf("invalid DSN: public key is missing")
+	}
+	if u.Host == "" {
+		return nil, serrors.Errorf("invalid DSN: host is missing")
+	}
+
+	p := strings.TrimSuffix(u.Path, "/")
+	i := strings.LastIndexByte(p, '/')
+	if i < 0 || p[i+1:] == "" {
+		return nil, serrors.Errorf("invalid DSN: project ID is missing")
+	}
+
+	secretKey, _ := u.User.Password()
+	return &DSN{
+		Scheme:    u.Scheme,
+		PublicKey: u.User.Username(),
+		SecretKey: secretKey,
+		Host:      u.Host,
+		Path:      p[:i],
+		ProjectID: p[i+1:],
+	}, nil
+}
+
+// StoreURL returns the URL of the store endpoint of the project.
+func (d *DSN) StoreURL() string {
+	return d.Scheme + "://" + d.Host + d.Path + "/api/" + d.ProjectID + "/store/"
+}
+
+// AuthHeader returns the value of the X-Sentry-Auth header.
+func (d *DSN) AuthHeader() string {
+	header := "Sentry sentry_version=" + sentryVersion + ", sentry_client=" + sentryClient + ", sentry_key=" + d.PublicKey
+	if d.SecretKey != "" {
+		header += ", sentry_secret=" + d.SecretKey
+	}
+	return header
+}
+
+// Transport sends Events to a Sentry server.
+type Transport struct {
+	dsn    *DSN
+	client *http.Client
+}
+
+// NewTransport creates a new Transport that sends Events to the project of the given DSN.
+//
+// If client is nil, http.DefaultClient is used.
+func NewTransport(dsn string, client *http.Client) (*Transport, error) {
+	parsed, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Transport{dsn: parsed, client: client}, nil
+}
+
+// Send sends the event to the Sentry server.
+//
+// If the server does not accept the event, the returned error contains the status code and the reason reported by the server.
+func (t *Transport) Send(ctx context.Context, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return serrors.Errorf("failed to encode the event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.dsn.StoreURL(), bytes.NewReader(body))
+	if err != nil {
+		return serrors.Errorf("failed to create the request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", t.dsn.AuthHeader())
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return serrors.Errorf("failed to send the event: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || 300 <= resp.StatusCode {
+		if reason := resp.Header.Get("X-Sentry-Error"); reason != "" {
+			return serrors.Errorf("the event was rejected: %s: %s", resp.Status, reason)
+		}
+		return serrors.Errorf("the event was rejected: %s", resp.Status)
+	}
+	return nil
+}
+
+// CaptureError creates an Event from err by NewEventWithOption and sends it to the Sentry server.
+//
+// It returns the ID of the sent Event.
+func (t *Transport) CaptureError(ctx context.Context, err error, opt EventOption) (string, error) {
+	event := NewEventWithOption(err, opt)
+	if sendErr := t.Send(ctx, event); sendErr != nil {
+		return "", sendErr
+	}
+	return event.EventID, nil
+}
@@ -0,0 +1,125 @@
+// Package serrslog provides log/slog integration for errors created by serrors.
+package serrslog
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/Siroshun09/serrors"
+)
+
+// NewHandler creates a new slog.Handler that expands error-valued attributes before passing records to next.
+//
+// Each error is replaced with a group that contains the following attributes:
+//
+//   - msg: the error message
+//   - chain: the messages of the wrapped errors
+//   - attributes: the attributes returned by serrors.Attributes (only if present)
+//   - stacktraces: the StackTraces returned by serrors.GetStackTraceEntries (only if present),
+//     with "omitted" if the StackTrace is truncated
+func NewHandler(next slog.Handler) slog.Handler {
+	return &handler{next: next}
+}
+
+type handler struct {
+	next slog.Handler
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	expanded := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		expanded.AddAttrs(expandAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, expanded)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		expanded[i] = expandAttr(attr)
+	}
+	return &handler{next: h.next.WithAttrs(expanded)}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{next: h.next.WithGroup(name)}
+}
+
+func expandAttr(attr slog.Attr) slog.Attr {
+	switch attr.Value.Kind() {
+	case slog.KindAny, slog.KindLogValuer:
+		if err, ok := attr.Value.Any().(error); ok && err != nil {
+			return slog.Attr{Key: attr.Key, Value: ErrorValue(err)}
+		}
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		expanded := make([]slog.Attr, len(group))
+		for i, attr := range group {
+			expanded[i] = expandAttr(attr)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(expanded...)}
+	default:
+	}
+	return attr
+}
+
+// ErrorValue returns the structured slog.Value of err that is used by the handler created by NewHandler.
+func ErrorValue(err error) slog.Value {
+	attrs := []slog.Attr{
+		slog.String("msg", err.Error()),
+		slog.Any("chain", chain(err)),
+	}
+
+	if errAttrs := serrors.Attributes(err); 0 < len(errAttrs) {
+		attrs = append(attrs, slog.Attr{Key: "attributes", Value: slog.GroupValue(errAttrs...)})
+	}
+
+	var stackTraces []slog.Attr
+	for entry := range serrors.GetStackTraceEntries(err) {
+		group := []any{slog.String("error", entry.Err.Error()), slog.Any("stacktrace", entry.StackTrace)}
+		if entry.Omitted != 0 {
+			group = append(group, slog.Int("omitted", entry.Omitted))
+		}
+		stackTraces = append(stackTraces, slog.Group(strconv.Itoa(len(stackTraces)), group...))
+	}
+	if 0 < len(stackTraces) {
+		attrs = append(attrs, slog.Attr{Key: "stacktraces", Value: slog.GroupValue(stackTraces...)})
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// chain returns the messages of err and its wrapped errors in depth-first order.
+//
+// Errors that have the same message as their parent (e.g. errors that only attach a StackTrace) are skipped.
+func chain(err error) []string {
+	var messages []string
+	collectMessages(err, "", &messages)
+	return messages
+}
+
+func collectMessages(err error, parent string, messages *[]string) {
+	if err == nil {
+		return
+	}
+
+	msg := err.Error()
+	if msg != parent {
+		*messages = append(*messages, msg)
+	}
+
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		collectMessages(x.Unwrap(), msg, messages)
+	case interface{ Unwrap() []error }:
+		for _, err := range x.Unwrap() {
+			collectMessages(err, msg, messages)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package serrslog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/Siroshun09/serrors"
+	"github.com/Siroshun09/serrors/serrslog"
+)
+
+type loggedStackTrace struct {
+	Error      string `json:"error"`
+	StackTrace struct {
+		Functions []string `json:"functions"`
+		Files     []string `json:"files"`
+		Lines     []int    `json:"lines"`
+	} `json:"stacktrace"`
+	Omitted int `json:"omitted"`
+}
+
+type loggedError struct {
+	Msg         string                      `json:"msg"`
+	Chain       []string                    `json:"chain"`
+	Attributes  map[string]any              `json:"attributes"`
+	StackTraces map[string]loggedStackTrace `json:"stacktraces"`
+}
+
+func TestHandler(t *testing.T) {
+	serr1 := serrors.New("test1")
+	serr2 := serrors.With(errors.New("test2"), "key", "value")
+	truncated := serrors.Decode(&serrors.Envelope{
+		Message:    "test3",
+		Kind:       serrors.EnvelopeKindStackTrace,
+		StackTrace: serrors.GetStackTrace(serr1)[:1],
+		Omitted:    2,
+		Causes:     []*serrors.Envelope{{Message: "test3"}},
+	})
+
+	tests := []struct {
+		name            string
+		err             error
+		wantChain       []string
+		wantAttributes  map[string]any
+		wantStackTraces map[string]serrors.StackTrace
+		wantOmitted     map[string]int
+	}{
+		{
+			name:      "no stack trace",
+			err:       fmt.Errorf("wrap: %w", errors.New("test")),
+			wantChain: []string{"wrap: test", "test"},
+		},
+		{
+			name:            "single stack trace",
+			err:             fmt.Errorf("wrap: %w", serr1),
+			wantChain:       []string{"wrap: test1", "test1"},
+			wantStackTraces: map[string]serrors.StackTrace{"0": serrors.GetStackTrace(serr1)},
+		},
+		{
+			name:            "joined errors",
+			err:             errors.Join(serr1, serr2),
+			wantChain:       []string{"test1\ntest2", "test1", "test2"},
+			wantAttributes:  map[string]any{"key": "value"},
+			wantStackTraces: map[string]serrors.StackTrace{"0": serrors.GetStackTrace(serr1), "1": serrors.GetStackTrace(serr2)},
+		},
+		{
+			name:            "truncated stack trace",
+			err:             truncated,
+			wantChain:       []string{"test3"},
+			wantStackTraces: map[string]serrors.StackTrace{"0": serrors.GetStackTrace(truncated)},
+			wantOmitted:     map[string]int{"0": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(serrslog.NewHandler(slog.NewJSONHandler(&buf, nil)))
+			logger.Error("failed", "err", tt.err)
+
+			var got struct {
+				Err loggedError `json:"err"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("failed to unmarshal %s: %v", buf.String(), err)
+			}
+
+			if got.Err.Msg != tt.err.Error() {
+				t.Errorf("msg = %v, want %v", got.Err.Msg, tt.err.Error())
+			}
+			if !reflect.DeepEqual(got.Err.Chain, tt.wantChain) {
+				t.Errorf("chain = %v, want %v", got.Err.Chain, tt.wantChain)
+			}
+			if !reflect.DeepEqual(got.Err.Attributes, tt.wantAttributes) {
+				t.Errorf("attributes = %v, want %v", got.Err.Attributes, tt.wantAttributes)
+			}
+			if len(got.Err.StackTraces) != len(tt.wantStackTraces) {
+				t.Fatalf("stacktraces = %v, want %v", got.Err.StackTraces, tt.wantStackTraces)
+			}
+			for key, want := range tt.wantStackTraces {
+				if omitted := got.Err.StackTraces[key].Omitted; omitted != tt.wantOmitted[key] {
+					t.Errorf("stacktraces[%s].omitted = %d, want %d", key, omitted, tt.wantOmitted[key])
+				}
+				logged := got.Err.StackTraces[key].StackTrace
+				for i, funcInfo := range want {
+					if logged.Functions[i] != funcInfo.Name || logged.Files[i] != funcInfo.File || logged.Lines[i] != funcInfo.Line {
+						t.Errorf("stacktraces[%s][%d] = (%s, %s, %d), want %v", key, i, logged.Functions[i], logged.Files[i], logged.Lines[i], funcInfo)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_WithAttrsAndGroup(t *testing.T) {
+	serr := serrors.New("test")
+
+	var buf bytes.Buffer
+	logger := slog.New(serrslog.NewHandler(slog.NewJSONHandler(&buf, nil)))
+	logger.With("attached", serr).WithGroup("group").Info("msg", slog.Group("nested", "err", serr), "plain", "value")
+
+	var got struct {
+		Attached loggedError `json:"attached"`
+		Group    struct {
+			Nested struct {
+				Err loggedError `json:"err"`
+			} `json:"nested"`
+			Plain string `json:"plain"`
+		} `json:"group"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", buf.String(), err)
+	}
+
+	if got.Attached.Msg != "test" || len(got.Attached.StackTraces) != 1 {
+		t.Errorf("attached = %+v, want expanded error", got.Attached)
+	}
+	if got.Group.Nested.Err.Msg != "test" || len(got.Group.Nested.Err.StackTraces) != 1 {
+		t.Errorf("group.nested.err = %+v, want expanded error", got.Group.Nested.Err)
+	}
+	if got.Group.Plain != "value" {
+		t.Errorf("group.plain = %v, want value", got.Group.Plain)
+	}
+}
+
+func TestHandler_Enabled(t *testing.T) {
+	h := serrslog.NewHandler(slog.NewJSONHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	if h.Enabled(t.Context(), slog.LevelInfo) {
+		t.Errorf("Enabled(Info) = true, want false")
+	}
+	if !h.Enabled(t.Context(), slog.LevelError) {
+		t.Errorf("Enabled(Error) = false, want true")
+	}
+}
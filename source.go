@@ -0,0 +1,115 @@
+package serrors
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SourceLine is a line of the source code around a frame, returned by FuncInfo.Source.
+type SourceLine struct {
+	// Number is the line number, starting at 1.
+	Number int
+	// Text is the content of the line without the line break.
+	Text string
+	// Current reports whether the line is FuncInfo.Line.
+	Current bool
+}
+
+// Source returns the source lines from Line-context to Line+context of the File, which is read from disk.
+//
+// The files are cached and read again only when their modification time or size changes.
+// If the file is not available or Line is out of its range, Source returns nil.
+func (s FuncInfo) Source(context int) []SourceLine {
+	if context < 0 || s.Line < 1 || s.File == "" {
+		return nil
+	}
+
+	lines := sources.lines(s.File)
+	if len(lines) < s.Line {
+		return nil
+	}
+
+	first := max(1, s.Line-context)
+	last := min(len(lines), s.Line+context)
+	source := make([]SourceLine, 0, last-first+1)
+	for n := first; n <= last; n++ {
+		source = append(source, SourceLine{Number: n, Text: lines[n-1], Current: n == s.Line})
+	}
+	return source
+}
+
+// StringWithSource formats the StackTrace like String, followed by context lines of the source code around each frame.
+//
+// The current line of each frame is marked by ">". Frames whose files are not available are formatted without the source code.
+func (s StackTrace) StringWithSource(context int) string {
+	var b strings.Builder
+	for i, funcInfo := range s {
+		if 0 < i {
+			b.WriteString("\n")
+		}
+		b.WriteString(funcInfo.String())
+
+		source := funcInfo.Source(context)
+		if len(source) == 0 {
+			continue
+		}
+
+		width := len(strconv.Itoa(source[len(source)-1].Number))
+		for _, line := range source {
+			b.WriteString("\n")
+			if line.Current {
+				b.WriteString("    > ")
+			} else {
+				b.WriteString("      ")
+			}
+			number := strconv.Itoa(line.Number)
+			b.WriteString(strings.Repeat(" ", width-len(number)) + number + " | " + line.Text)
+		}
+	}
+	return b.String()
+}
+
+// maxCachedSources is the maximum number of files cached by sourceCache.
+const maxCachedSources = 256
+
+var sources = &sourceCache{files: map[string]*sourceFile{}}
+
+type sourceCache struct {
+	mu    sync.Mutex
+	files map[string]*sourceFile
+}
+
+type sourceFile struct {
+	modTime time.Time
+	size    int64
+	// lines is nil if the file is not available.
+	lines []string
+}
+
+func (c *sourceCache) lines(path string) []string {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if file, ok := c.files[path]; ok && file.modTime.Equal(info.ModTime()) && file.size == info.Size() {
+		return file.lines
+	}
+
+	file := &sourceFile{modTime: info.ModTime(), size: info.Size()}
+	if data, err := os.ReadFile(path); err == nil {
+		file.lines = strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	}
+
+	if maxCachedSources <= len(c.files) {
+		clear(c.files)
+	}
+	c.files[path] = file
+	return file.lines
+}
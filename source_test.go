@@ -0,0 +1,122 @@
+package serrors
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeSourceFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFuncInfo_Source(t *testing.T) {
+	path := writeSourceFile(t, "package main\n\nfunc main() {\r\n\tpanic(\"boom\")\n}\n")
+
+	tests := []struct {
+		name     string
+		funcInfo FuncInfo
+		context  int
+		want     []SourceLine
+	}{
+		{
+			name:     "context",
+			funcInfo: FuncInfo{Name: "main.main", File: path, Line: 4},
+			context:  1,
+			want: []SourceLine{
+				{Number: 3, Text: "func main() {"},
+				{Number: 4, Text: "\tpanic(\"boom\")", Current: true},
+				{Number: 5, Text: "}"},
+			},
+		},
+		{
+			name:     "no context",
+			funcInfo: FuncInfo{Name: "main.main", File: path, Line: 4},
+			context:  0,
+			want:     []SourceLine{{Number: 4, Text: "\tpanic(\"boom\")", Current: true}},
+		},
+		{
+			name:     "first line",
+			funcInfo: FuncInfo{Name: "main.main", File: path, Line: 1},
+			context:  2,
+			want: []SourceLine{
+				{Number: 1, Text: "package main", Current: true},
+				{Number: 2, Text: ""},
+				{Number: 3, Text: "func main() {"},
+			},
+		},
+		{
+			name:     "out of range",
+			funcInfo: FuncInfo{Name: "main.main", File: path, Line: 100},
+			context:  1,
+		},
+		{
+			name:     "negative context",
+			funcInfo: FuncInfo{Name: "main.main", File: path, Line: 4},
+			context:  -1,
+		},
+		{
+			name:     "file not found",
+			funcInfo: FuncInfo{Name: "main.main", File: filepath.Join(t.TempDir(), "nonexistent.go"), Line: 1},
+			context:  1,
+		},
+		{
+			name:     "directory",
+			funcInfo: FuncInfo{Name: "main.main", File: t.TempDir(), Line: 1},
+			context:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.funcInfo.Source(tt.context); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Source() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuncInfo_Source_modified(t *testing.T) {
+	path := writeSourceFile(t, "first\n")
+	funcInfo := FuncInfo{Name: "main.main", File: path, Line: 1}
+	if got := funcInfo.Source(0); len(got) != 1 || got[0].Text != "first" {
+		t.Fatalf("Source() = %v, want first", got)
+	}
+
+	if err := os.WriteFile(path, []byte("modified\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := funcInfo.Source(0); len(got) != 1 || got[0].Text != "modified" {
+		t.Errorf("Source() = %v, want modified", got)
+	}
+}
+
+func TestStackTrace_StringWithSource(t *testing.T) {
+	lines := "package main\n\n"
+	for range 8 {
+		lines += "// comment\n"
+	}
+	lines += "func main() {\n\tpanic(\"boom\")\n}\n"
+	path := writeSourceFile(t, lines)
+
+	stackTrace := StackTrace{
+		{Name: "main.main", File: path, Line: 11},
+		{Name: "runtime.main", File: "/nonexistent/proc.go", Line: 283},
+	}
+	want := "main.main (" + path + ":11)\n" +
+		"       9 | // comment\n" +
+		"      10 | // comment\n" +
+		"    > 11 | func main() {\n" +
+		"      12 | \tpanic(\"boom\")\n" +
+		"      13 | }\n" +
+		"runtime.main (/nonexistent/proc.go:283)"
+	if got := stackTrace.StringWithSource(2); got != want {
+		t.Errorf("StringWithSource() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,171 @@
+package serrors
+
+import (
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// Node is a node of the error tree returned by Tree.
+//
+// The errors that only attach a StackTrace or attributes (created by this package) do not have their own nodes.
+// Instead, their StackTrace and attributes are set to the node of the wrapped error.
+type Node struct {
+	// Err is the error of this node.
+	Err error
+	// Message is the message of Err.
+	Message string
+	// Type is the concrete type name of Err, such as "*errors.errorString".
+	//
+	// For errors rebuilt by Decode, this is the type name of the original error.
+	Type string
+	// StackTrace is the StackTrace attached to Err, or nil if Err does not have one.
+	StackTrace StackTrace
+	// Omitted is the number of frames omitted from the end of StackTrace because of the max depth (see GetOmittedFrames).
+	Omitted int
+	// CreatedBy is the StackTrace of the goroutine that started the goroutine returning Err (see Go), or nil.
+	CreatedBy StackTrace
+	// CreatedByOmitted is the number of frames omitted from the end of CreatedBy because of the max depth.
+	CreatedByOmitted int
+	// Attributes are the attributes attached to Err by With.
+	Attributes []slog.Attr
+	// Children are the nodes of the errors wrapped by Err.
+	Children []*Node
+}
+
+// Tree returns the error tree of err.
+//
+// The tree is built by calling Unwrap() error and Unwrap() []error in the same way as GetStackTraces.
+//
+// If err is nil, this function returns nil.
+func Tree(err error) *Node {
+	if err == nil {
+		return nil
+	}
+
+	node := &Node{}
+	for folded := true; folded; {
+		switch x := err.(type) {
+		case *stackTraceError:
+			if node.StackTrace == nil {
+				node.StackTrace, node.Omitted = x.getStackTrace(), x.omitted
+			}
+			err = x.err
+		case *attrError:
+			node.Attributes = append(node.Attributes, x.attrs...)
+			err = x.err
+		case *goroutineError:
+			if node.CreatedBy == nil {
+				node.CreatedBy, node.CreatedByOmitted = x.getCreatedBy(), x.omitted
+			}
+			err = x.err
+		default:
+			folded = false
+		}
+	}
+
+	node.Err = err
+	node.Message = err.Error()
+	node.Type = reflect.TypeOf(err).String()
+	if remoteErr, ok := err.(*RemoteError); ok {
+		node.Type = remoteErr.Type
+	}
+
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		if child := Tree(x.Unwrap()); child != nil {
+			node.Children = []*Node{child}
+		}
+	case interface{ Unwrap() []error }:
+		for _, err := range x.Unwrap() {
+			if child := Tree(err); child != nil {
+				node.Children = append(node.Children, child)
+			}
+		}
+	}
+
+	return node
+}
+
+// String renders the tree as an indented ASCII tree, connecting the children with "|--", "`--" and "|" like the tree command.
+//
+// Each node is printed with its message and type, followed by its attributes and StackTraces.
+// The StackTraces are filtered by DefaultFrameFilters, and truncated StackTraces end with "... N more frames".
+func (n *Node) String() string {
+	var b strings.Builder
+	_, _ = n.WriteTo(&b)
+	return b.String()
+}
+
+// WriteTo writes the tree rendered by Node.String to w.
+func (n *Node) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	if n != nil {
+		n.render(cw, "", "")
+	}
+	return cw.n, cw.err
+}
+
+func (n *Node) render(w io.Writer, connector, indent string) {
+	lines := strings.Split(n.Message, "\n")
+	writeString(w, connector+lines[0]+" ["+n.Type+"]\n")
+	for _, line := range lines[1:] {
+		writeString(w, indent+line+"\n")
+	}
+
+	if 0 < len(n.Attributes) {
+		attrs := make([]string, len(n.Attributes))
+		for i, attr := range n.Attributes {
+			attrs[i] = attr.String()
+		}
+		writeString(w, indent+"attributes: "+strings.Join(attrs, " ")+"\n")
+	}
+
+	renderStackTrace(w, indent, "stacktrace:", n.StackTrace, n.Omitted)
+	renderStackTrace(w, indent, "created by:", n.CreatedBy, n.CreatedByOmitted)
+
+	for i, child := range n.Children {
+		if i == len(n.Children)-1 {
+			child.render(w, indent+"`-- ", indent+"    ")
+		} else {
+			child.render(w, indent+"|-- ", indent+"|   ")
+		}
+	}
+}
+
+func renderStackTrace(w io.Writer, indent, header string, stackTrace StackTrace, omitted int) {
+	if stackTrace == nil {
+		return
+	}
+
+	stackTrace = stackTrace.FilterDefault()
+
+	writeString(w, indent+header+"\n")
+	for _, funcInfo := range stackTrace {
+		writeString(w, indent+"  "+funcInfo.String()+"\n")
+	}
+	if omitted != 0 {
+		writeString(w, indent+"  "+FormatOmittedFrames(omitted)+"\n")
+	}
+}
+
+func writeString(w io.Writer, s string) {
+	_, _ = io.WriteString(w, s)
+}
+
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	w.err = err
+	return n, err
+}
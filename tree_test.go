@@ -0,0 +1,212 @@
+package serrors
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	stackTrace1 := StackTrace{{Name: "Test1", File: "test.go", Line: 1}}
+	stackTrace2 := StackTrace{{Name: "Test2", File: "test.go", Line: 2}}
+	base1 := errors.New("test1")
+	base2 := errors.New("test2")
+	plain := errors.New("plain")
+	serr1 := &stackTraceError{err: base1, stackTrace: stackTrace1}
+	serr2 := &attrError{err: &stackTraceError{err: base2, stackTrace: stackTrace2}, attrs: []slog.Attr{slog.Int("key", 1)}}
+	joined := errors.Join(serr1, plain, serr2)
+	wrapped := fmt.Errorf("wrap: %w", joined)
+
+	tests := []struct {
+		name string
+		err  error
+		want *Node
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "single error",
+			err:  base1,
+			want: &Node{Err: base1, Message: "test1", Type: "*errors.errorString"},
+		},
+		{
+			name: "stack trace",
+			err:  serr1,
+			want: &Node{Err: base1, Message: "test1", Type: "*errors.errorString", StackTrace: stackTrace1},
+		},
+		{
+			name: "goroutine error",
+			err:  &goroutineError{err: serr1, createdBy: stackTrace2},
+			want: &Node{Err: base1, Message: "test1", Type: "*errors.errorString", StackTrace: stackTrace1, CreatedBy: stackTrace2},
+		},
+		{
+			name: "joined errors",
+			err:  wrapped,
+			want: &Node{
+				Err:     wrapped,
+				Message: "wrap: test1\nplain\ntest2",
+				Type:    "*fmt.wrapError",
+				Children: []*Node{{
+					Err:     joined,
+					Message: "test1\nplain\ntest2",
+					Type:    "*errors.joinError",
+					Children: []*Node{
+						{Err: base1, Message: "test1", Type: "*errors.errorString", StackTrace: stackTrace1},
+						{Err: plain, Message: "plain", Type: "*errors.errorString"},
+						{Err: base2, Message: "test2", Type: "*errors.errorString", StackTrace: stackTrace2, Attributes: []slog.Attr{slog.Int("key", 1)}},
+					},
+				}},
+			},
+		},
+		{
+			name: "contains nil error in multiple errors",
+			err:  &multipleErrorsWrapper{errs: []error{nil, base1}},
+			want: &Node{
+				Err:      &multipleErrorsWrapper{errs: []error{nil, base1}},
+				Message:  "multiple errors",
+				Type:     "*serrors.multipleErrorsWrapper",
+				Children: []*Node{{Err: base1, Message: "test1", Type: "*errors.errorString"}},
+			},
+		},
+		{
+			name: "remote error",
+			err:  &RemoteError{Message: "test", Type: "*remote.Error"},
+			want: &Node{Err: &RemoteError{Message: "test", Type: "*remote.Error"}, Message: "test", Type: "*remote.Error"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tree(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tree() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNode_String(t *testing.T) {
+	serr1 := &stackTraceError{err: errors.New("test1"), stackTrace: StackTrace{{Name: "Test1", File: "test.go", Line: 1}, {Name: "Test2", File: "test.go", Line: 2}}}
+	serr2 := &attrError{err: &stackTraceError{err: errors.New("test2"), stackTrace: StackTrace{{Name: "Test3", File: "test.go", Line: 3}}}, attrs: []slog.Attr{slog.Int("key", 1)}}
+
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: []string{""},
+		},
+		{
+			name: "single error",
+			err:  serr1,
+			want: []string{
+				"test1 [*errors.errorString]",
+				"stacktrace:",
+				"  Test1 (test.go:1)",
+				"  Test2 (test.go:2)",
+				"",
+			},
+		},
+		{
+			name: "joined errors",
+			err:  fmt.Errorf("wrap: %w", errors.Join(serr1, fmt.Errorf("wrap: %w", errors.New("plain")), serr2)),
+			want: []string{
+				"wrap: test1 [*fmt.wrapError]",
+				"wrap: plain",
+				"test2",
+				"`-- test1 [*errors.joinError]",
+				"    wrap: plain",
+				"    test2",
+				"    |-- test1 [*errors.errorString]",
+				"    |   stacktrace:",
+				"    |     Test1 (test.go:1)",
+				"    |     Test2 (test.go:2)",
+				"    |-- wrap: plain [*fmt.wrapError]",
+				"    |   `-- plain [*errors.errorString]",
+				"    `-- test2 [*errors.errorString]",
+				"        attributes: key=1",
+				"        stacktrace:",
+				"          Test3 (test.go:3)",
+				"",
+			},
+		},
+		{
+			name: "created by",
+			err:  &goroutineError{err: errors.New("test"), createdBy: StackTrace{{Name: "Test", File: "test.go", Line: 1}}},
+			want: []string{
+				"test [*errors.errorString]",
+				"created by:",
+				"  Test (test.go:1)",
+				"",
+			},
+		},
+		{
+			name: "omitted frames",
+			err: &goroutineError{
+				err:       &stackTraceError{err: errors.New("test"), omitted: 1, stackTrace: StackTrace{{Name: "Test1", File: "test.go", Line: 1}}},
+				omitted:   -1,
+				createdBy: StackTrace{{Name: "Test2", File: "test.go", Line: 2}},
+			},
+			want: []string{
+				"test [*errors.errorString]",
+				"stacktrace:",
+				"  Test1 (test.go:1)",
+				"  ... 1 more frame",
+				"created by:",
+				"  Test2 (test.go:2)",
+				"  ... more frames",
+				"",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := strings.Join(tt.want, "\n")
+			if got := Tree(tt.err).String(); got != want {
+				t.Errorf("String() = \n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+
+	t.Run("default frame filters", func(t *testing.T) {
+		t.Cleanup(func() { SetDefaultFrameFilters() })
+		SetDefaultFrameFilters(func(funcInfo FuncInfo) bool { return funcInfo.Name != "Test2" })
+
+		want := "test1 [*errors.errorString]\nstacktrace:\n  Test1 (test.go:1)\n"
+		if got := Tree(serr1).String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestNode_WriteTo(t *testing.T) {
+	node := Tree(errors.New("test"))
+
+	var b strings.Builder
+	n, err := node.WriteTo(&b)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if b.String() != node.String() || n != int64(b.Len()) {
+		t.Errorf("WriteTo() = (%d, %q), want (%d, %q)", n, b.String(), len(node.String()), node.String())
+	}
+
+	if _, err := node.WriteTo(errWriter{}); !errors.Is(err, errWrite) {
+		t.Errorf("WriteTo() error = %v, want %v", err, errWrite)
+	}
+}
+
+var errWrite = errors.New("write error")
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errWrite
+}